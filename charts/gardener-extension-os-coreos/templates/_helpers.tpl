@@ -58,10 +58,3 @@ app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
 {{- end }}
 app.kubernetes.io/managed-by: {{ .Release.Service }}
 {{- end }}
-
-{{/*
-Get checksum of config secret or configMap
-*/}}
-{{- define "coreos.configChecksum" -}}
-{{- include (print $.Template.BasePath "/secret-config.yaml") . | sha256sum -}}
-{{- end -}}
\ No newline at end of file
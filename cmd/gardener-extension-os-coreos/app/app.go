@@ -43,6 +43,7 @@ func NewControllerCommand() *cobra.Command {
 	}
 
 	options.optionAggregator.AddFlags(cmd.Flags())
+	cmd.AddCommand(newSchemaCommand())
 
 	return cmd
 }
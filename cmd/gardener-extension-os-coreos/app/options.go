@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 
 	extensionscmdcontroller "github.com/gardener/gardener/extensions/pkg/controller/cmd"
 	"github.com/gardener/gardener/extensions/pkg/controller/heartbeat"
@@ -12,10 +13,13 @@ import (
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
-	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+	runtimelog "sigs.k8s.io/controller-runtime/pkg/log"
 
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/install"
 	configv1alpha1 "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
-	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1/validation"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/validation"
 	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/operatingsystemconfig"
 )
 
@@ -35,23 +39,30 @@ type Options struct {
 // ExtensionOptions holds options related to the extension (not the extension controller)
 type ExtensionOptions struct {
 	configFile string
-	Config     *configv1alpha1.ExtensionConfig
+	// lenientConfigDecoding ignores unknown and duplicate fields in the config file and the provider config of shoots.
+	// It is only meant for the transition to strict decoding and will be removed again.
+	lenientConfigDecoding bool
+	// osTypes are the types of operating system configs the extension handles.
+	osTypes []string
+	// validateShoots serves the webhook validating the provider config of the machine images of shoots.
+	validateShoots bool
+	Config         *coreosconfig.ExtensionConfig
 }
 
 var configDecoder runtime.Decoder
 
 func init() {
 	configScheme := runtime.NewScheme()
-	schemeBuilder := runtime.NewSchemeBuilder(
-		configv1alpha1.AddToScheme,
-	)
-	utilruntime.Must(schemeBuilder.AddToScheme(configScheme))
-	configDecoder = serializer.NewCodecFactory(configScheme).UniversalDecoder()
+	install.Install(configScheme)
+	configDecoder = serializer.NewCodecFactory(configScheme, serializer.EnableStrict).UniversalDecoder()
 }
 
 // AddFlags implements Flagger.AddFlags.
 func (o *ExtensionOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.configFile, "config", o.configFile, "Path to configuration file.")
+	fs.StringSliceVar(&o.osTypes, "os-types", operatingsystemconfig.DefaultTypes, "Types of operating system configs the extension handles. They must match the resources of the ControllerRegistration.")
+	fs.BoolVar(&o.lenientConfigDecoding, "lenient-config-decoding", o.lenientConfigDecoding, "Ignore unknown and duplicate fields in the configuration file and the provider config of shoots instead of failing. Deprecated: only meant for the transition to strict decoding.")
+	fs.BoolVar(&o.validateShoots, "validate-shoots", o.validateShoots, "Serve a webhook at "+operatingsystemconfig.ShootValidatorPath+", which validates the provider config of the machine images of shoots. Requires the webhook server flags.")
 }
 
 // Complete implements Completer.Complete.
@@ -65,14 +76,46 @@ func (o *ExtensionOptions) Complete() error {
 		return fmt.Errorf("error reading config file: %w", err)
 	}
 
-	o.Config = &configv1alpha1.ExtensionConfig{}
-	if err = runtime.DecodeInto(configDecoder, data, o.Config); err != nil {
-		return fmt.Errorf("error decoding config: %w", err)
+	config, err := o.decodeConfig(data)
+	if err != nil {
+		return err
 	}
+	o.Config = config
 
 	return nil
 }
 
+// decodeConfig decodes the given content of the config file.
+func (o *ExtensionOptions) decodeConfig(data []byte) (*coreosconfig.ExtensionConfig, error) {
+	// Config files without apiVersion and kind are decoded as v1alpha1, which was the only version before.
+	obj, _, err := configDecoder.Decode(data, ptr.To(configv1alpha1.SchemeGroupVersion.WithKind("ExtensionConfig")), nil)
+	if err != nil {
+		// The strict decoder still decodes and defaults the known fields if there are unknown or duplicate ones.
+		if !o.lenientConfigDecoding || !runtime.IsStrictDecodingError(err) {
+			return nil, fmt.Errorf("error decoding config: %w", err)
+		}
+		runtimelog.Log.Info("Ignoring unknown or duplicate fields in config file", "file", o.configFile, "errors", err.Error())
+	}
+
+	config, ok := obj.(*coreosconfig.ExtensionConfig)
+	if !ok {
+		return nil, fmt.Errorf("error decoding config: unexpected type %T", obj)
+	}
+	return config, nil
+}
+
+// loadConfig decodes and validates the given content of the config file, which changed while the extension is running.
+func (o *ExtensionOptions) loadConfig(data []byte) (*coreosconfig.ExtensionConfig, error) {
+	config, err := o.decodeConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.validateConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 func (o *ExtensionOptions) Completed() *ExtensionOptions {
 	return o
 }
@@ -80,12 +123,32 @@ func (o *ExtensionOptions) Completed() *ExtensionOptions {
 // Apply applies the ExtensionOptions to the passed ControllerConfig instance.
 func (o *ExtensionOptions) Apply(config *operatingsystemconfig.Config) {
 	config.ExtensionConfig = o.Config
+	config.LenientDecoding = o.lenientConfigDecoding
+	config.Types = o.osTypes
+	config.ConfigFile = o.configFile
+	config.LoadExtensionConfig = o.loadConfig
+	config.ValidateShoots = o.validateShoots
 }
 
 func (o *ExtensionOptions) Validate() error {
-	if errs := validation.ValidateExtensionConfig(o.Config); len(errs) > 0 {
+	if len(o.osTypes) == 0 {
+		return errors.New("at least one operating system config type is required")
+	}
+	return o.validateConfig(o.Config)
+}
+
+// validateConfig validates the given extension config, also against the types handled by the extension.
+func (o *ExtensionOptions) validateConfig(config *coreosconfig.ExtensionConfig) error {
+	if errs := validation.ValidateExtensionConfig(config); len(errs) > 0 {
 		return fmt.Errorf("invalid extension config: %w", errs.ToAggregate())
 	}
+	for _, override := range config.TypeOverrides {
+		for _, t := range override.Types {
+			if !slices.Contains(o.osTypes, t) {
+				return fmt.Errorf("invalid extension config: type override for %q, which is not handled by the extension", t)
+			}
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/schema"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+)
+
+// newSchemaCommand creates a command printing the schema of the ExtensionConfig, which is embedded in the binary.
+func newSchemaCommand() *cobra.Command {
+	var apiVersion string
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the OpenAPI v3 schema of the ExtensionConfig",
+		Long:  "Print the OpenAPI v3 schema of the ExtensionConfig, which is used both for the config of the extension and the provider config of the machine images of shoots. It contains the descriptions and allowed values of the fields.",
+		Args:  cobra.NoArgs,
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			raw, err := schema.ForVersion(apiVersion)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(raw)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&apiVersion, "api-version", v1beta1.SchemeGroupVersion.Version, "API version of the ExtensionConfig, v1alpha1 or v1beta1")
+
+	return cmd
+}
@@ -1,7 +1,8 @@
-// Package config is a dummy package to make k8s.io/code-generator work without an internal version.
+// +k8s:deepcopy-gen=package
 
+// Package config contains the internal version of the API for configuring the os-coreos extension.
 // +groupName=config.coreos.os.extensions.gardener.cloud
 
 //go:generate ../../../hack/update-codegen.sh
 
-package config
+package config // import "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package install
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+)
+
+var (
+	schemeBuilder = runtime.NewSchemeBuilder(
+		v1alpha1.AddToScheme,
+		v1beta1.AddToScheme,
+		config.AddToScheme,
+		setVersionPriority,
+	)
+
+	// AddToScheme adds all API types to the given scheme.
+	AddToScheme = schemeBuilder.AddToScheme
+)
+
+func setVersionPriority(scheme *runtime.Scheme) error {
+	return scheme.SetVersionPriority(v1beta1.SchemeGroupVersion, v1alpha1.SchemeGroupVersion)
+}
+
+// Install installs all API versions of the config group in the scheme.
+func Install(scheme *runtime.Scheme) {
+	utilruntime.Must(AddToScheme(scheme))
+}
@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build ignore
+
+// Generates the schemas of the ExtensionConfig of all API versions, run with go generate.
+package main
+
+import (
+	"log"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/schema/generator"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+)
+
+func main() {
+	for _, gv := range []schema.GroupVersion{v1alpha1.SchemeGroupVersion, v1beta1.SchemeGroupVersion} {
+		out, err := generator.Generate(generator.PackagePath(gv.Version), gv.String())
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(gv.Version+".json", out, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package generator generates the schema of the ExtensionConfig with the CRD schema generator of controller-tools, which
+// is also used for the CRDs of Gardener. It is only used by go generate and the tests, so that the binaries do not depend
+// on controller-tools.
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/tools/go/packages"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-tools/pkg/crd"
+	crdmarkers "sigs.k8s.io/controller-tools/pkg/crd/markers"
+	"sigs.k8s.io/controller-tools/pkg/loader"
+	"sigs.k8s.io/controller-tools/pkg/markers"
+)
+
+// PackagePath returns the import path of the Go package of the given API version of the ExtensionConfig.
+func PackagePath(version string) string {
+	return "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/" + version
+}
+
+// Generate returns the OpenAPI v3 schema of the ExtensionConfig in the Go package with the given import path, which
+// contains the API version with the given name. The doc comments of the types and fields are used as descriptions and
+// the validation markers, e.g. +kubebuilder:validation:Enum, as restrictions of the values. The config of overrides and
+// profiles is described by the settings of the top level, which they may contain.
+func Generate(pkgPath, apiVersion string) ([]byte, error) {
+	roots, err := loader.LoadRoots(pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("expected a single package for %s, got %d", pkgPath, len(roots))
+	}
+
+	registry := &markers.Registry{}
+	if err := crdmarkers.Register(registry); err != nil {
+		return nil, err
+	}
+	parser := &crd.Parser{
+		Collector: &markers.Collector{Registry: registry},
+		Checker:   &loader.TypeChecker{},
+	}
+	crd.AddKnownTypes(parser)
+
+	typ := crd.TypeIdent{Package: roots[0], Name: "ExtensionConfig"}
+	parser.NeedPackage(typ.Package)
+	withoutNestedConfigs(parser, typ)
+	parser.NeedFlattenedSchemaFor(typ)
+	// Like controller-gen, only errors of the API package count, the dependencies are type checked partially.
+	if loader.PrintErrors(roots, packages.TypeError) {
+		return nil, fmt.Errorf("failed to generate the schema of %s", typ)
+	}
+	schema, ok := parser.FlattenedSchemata[typ]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found", typ)
+	}
+
+	withNestedConfigs(&schema)
+
+	// The type meta is required to decode the config in the given version.
+	schema.Properties["apiVersion"] = withEnum(schema.Properties["apiVersion"], apiVersion)
+	schema.Properties["kind"] = withEnum(schema.Properties["kind"], "ExtensionConfig")
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// withoutNestedConfigs replaces the references to the given type in the schemas of its package with objects without
+// properties. The config of overrides and profiles is an ExtensionConfig again, and flattening the recursion would
+// otherwise cut it at arbitrary depths. The objects are replaced by withNestedConfigs after flattening.
+func withoutNestedConfigs(parser *crd.Parser, typ crd.TypeIdent) {
+	ref := crd.TypeRefLink("", typ.Name)
+	for ident := range parser.Types {
+		if ident.Package != typ.Package {
+			continue
+		}
+		parser.NeedSchemaFor(ident)
+		schema := parser.Schemata[ident]
+		crd.EditSchema(&schema, nestedConfigVisitor{ref: ref})
+		parser.Schemata[ident] = schema
+	}
+}
+
+type nestedConfigVisitor struct {
+	ref string
+}
+
+func (v nestedConfigVisitor) Visit(schema *apiextensionsv1.JSONSchemaProps) crd.SchemaVisitor {
+	if schema == nil || schema.Ref == nil || *schema.Ref != v.ref {
+		return v
+	}
+	*schema = apiextensionsv1.JSONSchemaProps{
+		Description:            schema.Description,
+		Type:                   "object",
+		XPreserveUnknownFields: ptr.To(true),
+	}
+	return nil
+}
+
+// withNestedConfigs describes the config of the overrides and profiles of the given schema of the ExtensionConfig with
+// the fields, which are allowed there by the validation of the extension. Worker pool overrides and profiles may only
+// contain settings, type overrides may also contain image version rules and worker pool overrides. The nested configs
+// are not defaulted, so that they do not overwrite the settings they are merged into, hence the defaults are dropped.
+func withNestedConfigs(schema *apiextensionsv1.JSONSchemaProps) {
+	settings := nestedConfig(*schema, "policy", "typeOverrides", "imageVersionRules", "workerPoolOverrides", "profiles", "profile")
+	setNestedConfig(schema, "workerPoolOverrides", settings)
+	setNestedConfig(schema, "profiles", settings)
+	setNestedConfig(schema, "typeOverrides", nestedConfig(*schema, "policy", "typeOverrides", "profiles"))
+}
+
+// nestedConfig returns a copy of the given schema of the ExtensionConfig without the type meta, the given fields and
+// the defaults.
+func nestedConfig(schema apiextensionsv1.JSONSchemaProps, excludedFields ...string) apiextensionsv1.JSONSchemaProps {
+	config := *schema.DeepCopy()
+	for _, name := range append(excludedFields, "apiVersion", "kind") {
+		delete(config.Properties, name)
+	}
+	crd.EditSchema(&config, withoutDefaultsVisitor{})
+	return config
+}
+
+// setNestedConfig sets the given schema as the config of the items of the list with the given name, keeping the
+// description of the config.
+func setNestedConfig(schema *apiextensionsv1.JSONSchemaProps, name string, config apiextensionsv1.JSONSchemaProps) {
+	list := schema.Properties[name]
+	item := list.Items.Schema
+	config.Description = item.Properties["config"].Description
+	item.Properties["config"] = config
+	schema.Properties[name] = list
+}
+
+type withoutDefaultsVisitor struct{}
+
+func (v withoutDefaultsVisitor) Visit(schema *apiextensionsv1.JSONSchemaProps) crd.SchemaVisitor {
+	if schema != nil {
+		schema.Default = nil
+	}
+	return v
+}
+
+func withEnum(schema apiextensionsv1.JSONSchemaProps, value string) apiextensionsv1.JSONSchemaProps {
+	raw, _ := json.Marshal(value)
+	schema.Enum = []apiextensionsv1.JSON{{Raw: raw}}
+	return schema
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate go run generate_schema.go
+
+// Package schema contains the OpenAPI v3 schemas of the ExtensionConfig, e.g. for editors and linters of provider configs.
+package schema
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+)
+
+var (
+	// V1alpha1 is the schema of the v1alpha1 ExtensionConfig, generated from the types of the API.
+	//
+	//go:embed v1alpha1.json
+	V1alpha1 []byte
+	// V1beta1 is the schema of the v1beta1 ExtensionConfig, generated from the types of the API.
+	//
+	//go:embed v1beta1.json
+	V1beta1 []byte
+)
+
+// ForVersion returns the schema of the ExtensionConfig in the given API version, e.g. v1beta1.
+func ForVersion(version string) ([]byte, error) {
+	switch version {
+	case v1alpha1.SchemeGroupVersion.Version:
+		return V1alpha1, nil
+	case v1beta1.SchemeGroupVersion.Version:
+		return V1beta1, nil
+	}
+	return nil, fmt.Errorf("unknown API version %q, must be one of %s or %s", version, v1alpha1.SchemeGroupVersion.Version, v1beta1.SchemeGroupVersion.Version)
+}
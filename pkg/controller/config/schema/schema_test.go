@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/schema"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/schema/generator"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+)
+
+var _ = Describe("Schema", func() {
+	DescribeTableSubtree("API versions", func(gv schema.GroupVersion) {
+		var props *apiextensionsv1.JSONSchemaProps
+
+		BeforeEach(func() {
+			raw, err := ForVersion(gv.Version)
+			Expect(err).NotTo(HaveOccurred())
+			props = &apiextensionsv1.JSONSchemaProps{}
+			Expect(json.Unmarshal(raw, props)).To(Succeed())
+		})
+
+		It("should be up to date with the API", func() {
+			raw, err := ForVersion(gv.Version)
+			Expect(err).NotTo(HaveOccurred())
+			generated, err := generator.Generate(generator.PackagePath(gv.Version), gv.String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(raw)).To(Equal(string(generated)), "the schema is stale, run go generate ./pkg/controller/config/schema")
+		})
+
+		It("should only accept the API version", func() {
+			Expect(props.Properties["apiVersion"].Enum).To(ConsistOf(apiextensionsv1.JSON{Raw: []byte(`"` + gv.String() + `"`)}))
+		})
+
+		It("should contain the daemons as enum", func() {
+			Expect(props.Properties["ntp"].Properties["daemon"].Enum).To(ConsistOf(
+				apiextensionsv1.JSON{Raw: []byte(`"` + v1beta1.SystemdTimesyncd + `"`)},
+				apiextensionsv1.JSON{Raw: []byte(`"` + v1beta1.NTPD + `"`)},
+				apiextensionsv1.JSON{Raw: []byte(`"` + v1beta1.Chrony + `"`)},
+			))
+		})
+
+		It("should contain the defaults of the extension", func() {
+			Expect(props.Properties["enableDocker"].Default).To(Equal(&apiextensionsv1.JSON{Raw: []byte(`false`)}))
+			Expect(props.Properties["ntp"].Default).To(Equal(&apiextensionsv1.JSON{Raw: []byte(`{}`)}))
+			Expect(props.Properties["ntp"].Properties["enabled"].Default).To(Equal(&apiextensionsv1.JSON{Raw: []byte(`true`)}))
+			Expect(props.Properties["ntp"].Properties["daemon"].Default).To(Equal(&apiextensionsv1.JSON{Raw: []byte(`"systemd-timesyncd"`)}))
+			Expect(props.Properties["containerd"].Properties["snapshotter"].Default).To(Equal(&apiextensionsv1.JSON{Raw: []byte(`"overlayfs"`)}))
+		})
+
+		DescribeTable("should describe the settings of nested configs without defaults",
+			func(list string, fields ...string) {
+				config := props.Properties[list].Items.Schema.Properties["config"]
+				Expect(config.Description).To(HavePrefix("Config Settings merged into"))
+				Expect(config.XPreserveUnknownFields).To(BeNil())
+				Expect(slices.Collect(maps.Keys(config.Properties))).To(ConsistOf(fields))
+				Expect(config.Properties["ntp"].Properties["daemon"].Enum).To(HaveLen(3))
+				Expect(config.Properties["enableDocker"].Default).To(BeNil())
+				Expect(config.Properties["ntp"].Default).To(BeNil())
+				Expect(config.Properties["ntp"].Properties["daemon"].Default).To(BeNil())
+			},
+			Entry("worker pool overrides", "workerPoolOverrides", "enableDocker", "ntp", "sysctl", "containerd"),
+			Entry("profiles", "profiles", "enableDocker", "ntp", "sysctl", "containerd"),
+			Entry("type overrides", "typeOverrides", "enableDocker", "ntp", "sysctl", "containerd", "imageVersionRules", "workerPoolOverrides", "profile"),
+		)
+
+		It("should describe the worker pool overrides of type overrides", func() {
+			config := props.Properties["typeOverrides"].Items.Schema.Properties["config"].Properties["workerPoolOverrides"].Items.Schema.Properties["config"]
+			Expect(slices.Collect(maps.Keys(config.Properties))).To(ConsistOf("enableDocker", "ntp", "sysctl", "containerd"))
+		})
+	},
+		Entry("v1alpha1", v1alpha1.SchemeGroupVersion),
+		Entry("v1beta1", v1beta1.SchemeGroupVersion),
+	)
+
+	It("should fail for unknown API versions", func() {
+		_, err := ForVersion("v1")
+		Expect(err).To(MatchError(ContainSubstring(`unknown API version "v1"`)))
+	})
+})
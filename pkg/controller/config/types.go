@@ -0,0 +1,282 @@
+package config
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Daemon string
+
+const (
+	SystemdTimesyncd Daemon = "systemd-timesyncd"
+	NTPD             Daemon = "ntpd"
+	Chrony           Daemon = "chrony"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExtensionConfig is the configuration for the os-coreos extension.
+type ExtensionConfig struct {
+	metav1.TypeMeta
+
+	// EnableDocker specifies if docker should be available on the nodes.
+	// Defaults to false, as docker is only need for special use-cases.
+	EnableDocker *bool
+	// NTP to configure either systemd-timesyncd or ntpd
+	NTP *NTPConfig
+	// Sysctl to configure kernel parameters on the nodes
+	Sysctl *SysctlConfig
+	// Containerd to configure the containerd configuration file written during node provisioning
+	Containerd *ContainerdConfig
+	// Policy Restrictions for the provider config of shoots, only allowed in the extension config
+	Policy *PolicyConfig
+	// TypeOverrides Settings for operating system configs of specific types, e.g. flatcar-lts, only allowed in the
+	// extension config. They are merged into the extension config in the given order before the provider config of the
+	// shoot.
+	TypeOverrides []TypeOverride
+	// ImageVersionRules Customizations enabled or disabled for machine image versions, only allowed in the extension
+	// config and type overrides. Later rules take precedence over earlier ones.
+	ImageVersionRules []ImageVersionRule
+	// WorkerPoolOverrides Settings for the worker pools with the given names, e.g. to enable docker only on some pools
+	// sharing a machine image. They are merged in the given order after the provider config of the shoot.
+	WorkerPoolOverrides []WorkerPoolOverride
+	// Profiles Named presets of settings, only allowed in the extension config
+	Profiles []ConfigProfile
+	// Profile Name of the profile whose settings are merged before the provider config of the shoot. It is usually
+	// selected in the provider config, the one of the extension config is used if the shoot does not select a profile.
+	Profile string
+}
+
+// Customization is an adjustment of the extension, which is only needed for some machine image versions
+type Customization string
+
+const (
+	// CustomizationLogrotatePath points containerd-logrotate.service to /usr/bin/logrotate, where some Flatcar versions
+	// ship logrotate instead of /usr/sbin/logrotate. If no rule applies, the path is checked on the node.
+	CustomizationLogrotatePath Customization = "logrotate-path"
+	// CustomizationMaskSysupdate masks the timers of systemd-sysupdate. It is enabled unless a rule disables it.
+	CustomizationMaskSysupdate Customization = "mask-sysupdate"
+)
+
+// ImageVersionRule enables or disables customizations for the machine image versions matching a semver constraint
+type ImageVersionRule struct {
+	// Versions Semver constraint of the machine image versions, e.g. ">= 3975.2.0"
+	Versions string
+	// Enable Customizations applied to matching machine images
+	Enable []Customization
+	// Disable Customizations not applied to matching machine images
+	Disable []Customization
+}
+
+// TypeOverride contains settings for operating system configs of specific types
+type TypeOverride struct {
+	// Types of the operating system configs the settings apply to
+	Types []string
+	// Config Settings merged into the extension config in the same way as the provider config of shoots. Policy and
+	// type overrides must not be set.
+	Config ExtensionConfig
+}
+
+// WorkerPoolOverride contains settings for the worker pools with specific names
+type WorkerPoolOverride struct {
+	// Pools Names of the worker pools the settings apply to
+	Pools []string
+	// Config Settings merged into the config in the same way as the provider config of shoots. Only settings, which are
+	// allowed in the provider config, may be set.
+	Config ExtensionConfig
+}
+
+// ConfigProfile is a named preset of settings shoots can select
+type ConfigProfile struct {
+	// Name of the profile, e.g. hardened
+	Name string
+	// Config Settings merged into the config in the same way as the provider config of shoots. Only settings, which are
+	// allowed in the provider config, may be set.
+	Config ExtensionConfig
+}
+
+// PolicyConfig restricts which fields shoots may set in the provider config of the machine image. Fields are addressed
+// by their path as in validation errors, e.g. ntp.ntpd.servers or sysctl.settings[vm.max_map_count].
+type PolicyConfig struct {
+	// LockedFields Paths of the fields shoots must neither set nor clear. Locking a field also locks its subfields.
+	LockedFields []string
+	// AllowedValues Values shoots may set a field to
+	AllowedValues []AllowedValues
+}
+
+// AllowedValues restricts the values shoots may set a field to
+type AllowedValues struct {
+	// Field Path of a field with a scalar value or a list of scalar values, e.g. ntp.daemon or sysctl.profiles
+	Field string
+	// Values Allowed values of the field, each element of a list must be one of them
+	Values []string
+}
+
+// NTPConfig General NTP Config for either systemd-timesyncd or ntpd
+type NTPConfig struct {
+	// Enabled Optionally disable or enable the extension to configure a timesync service for the machine
+	Enabled *bool
+	// Daemon One of systemd-timesyncd, ntpd or chrony
+	Daemon Daemon
+	// NTPD to configure the ntpd client
+	NTPD *NTPDConfig
+	// Timesyncd to configure the systemd-timesyncd client
+	Timesyncd *TimesyncdConfig
+	// Chrony to configure the chrony client
+	Chrony *ChronyConfig
+	// RequireAuthentication Only accept authenticated time, i.e. NTS for all chrony sources or key-based authentication
+	// for ntpd. systemd-timesyncd does not support authentication.
+	RequireAuthentication *bool
+	// WaitForSync Delay the start of the kubelet until the clock is synchronized
+	WaitForSync *NTPWaitForSync
+}
+
+// NTPWaitForSync configures whether the kubelet waits for the clock to be synchronized on boot
+type NTPWaitForSync struct {
+	// Enabled Order the kubelet after time-sync.target, which is reached once the clock is synchronized
+	Enabled bool
+	// Timeout after which the kubelet is started even if the clock is not synchronized. Defaults to 2m.
+	Timeout *metav1.Duration
+}
+
+// NTPDConfig is the struct used in the ntp-config.conf.tpl template file
+type NTPDConfig struct {
+	// Servers List of ntp servers
+	Servers []string
+	// Sources List of ntp servers and pools with individual options, in addition to the servers
+	Sources []NTPDSource
+	// Interfaces for ntpd to bind to. Can be more than one.
+	Interfaces []string
+	// Authentication Symmetric key used to authenticate the servers
+	Authentication *NTPDAuthentication
+	// Restrict Access restrictions, each rendered as restrict line. Defaults to restricting all hosts but localhost.
+	Restrict []string
+	// DriftFile Path of the drift file. Defaults to /var/lib/ntp/ntp.drift.
+	DriftFile *string
+}
+
+// NTPDSource is a server or pool ntpd obtains the time from
+type NTPDSource struct {
+	// Address Host name or IP address of the server or pool
+	Address string
+	// Pool Use multiple servers the address resolves to
+	Pool bool
+	// Prefer Prefer the source over the others
+	Prefer bool
+	// MinPoll Minimum poll interval as power of two in seconds, between 3 and 17
+	MinPoll *int32
+	// MaxPoll Maximum poll interval as power of two in seconds, between 3 and 17
+	MaxPoll *int32
+	// Key ID of the key to authenticate the source with, which must be the key of the authentication
+	Key *int32
+}
+
+// NTPDAuthentication is the symmetric key ntpd uses to authenticate the servers
+type NTPDAuthentication struct {
+	// KeyID ID of the key as configured on the servers, between 1 and 65535
+	KeyID int32
+	// Type of the key, one of MD5, SHA1 or AES128CMAC
+	Type string
+	// SecretRef Reference to a Secret in the namespace of the OperatingSystemConfig containing the key in the data key key.
+	// The provider config of a shoot may only reference the resources of the shoot, whose names have the prefix ref-.
+	SecretRef corev1.LocalObjectReference
+}
+
+// TimesyncdConfig is the struct used in the timesyncd.conf.tpl template file
+type TimesyncdConfig struct {
+	// Servers List of ntp servers
+	Servers []string
+	// FallbackServers List of ntp servers used if none of the servers can be reached. Defaults to the servers of the image.
+	FallbackServers []string
+	// PollIntervalMin Minimum poll interval, at least 16s. Defaults to 32s.
+	PollIntervalMin *metav1.Duration
+	// PollIntervalMax Maximum poll interval, at least the minimum poll interval. Defaults to 2048s.
+	PollIntervalMax *metav1.Duration
+}
+
+// ChronyConfig is the struct used in the chrony.conf.tpl template file
+type ChronyConfig struct {
+	// Servers List of ntp servers
+	Servers []ChronySource
+	// Pools List of ntp pools, chrony uses multiple servers of each pool
+	Pools []ChronySource
+	// RefClocks List of PTP hardware clocks, e.g. the one of the hypervisor
+	RefClocks []ChronyRefClock
+	// MakeStep Step the clock instead of slewing it during the first updates. Defaults to a threshold of 1s in the first 3 updates.
+	MakeStep *ChronyMakeStep
+	// RTCSync Periodically copy the system time to the real-time clock. Defaults to true.
+	RTCSync *bool
+	// Allow Networks in CIDR notation, which may use the node as ntp server
+	Allow []string
+	// NTSTrustedCertificates PEM encoded CA certificates to verify the NTS-KE servers, in addition to the system ones
+	NTSTrustedCertificates *string
+}
+
+// ChronySource is a server or pool chrony obtains the time from
+type ChronySource struct {
+	// Address Host name or IP address of the server or pool
+	Address string
+	// NTS Authenticate the source with Network Time Security
+	NTS bool
+}
+
+// ChronyRefClock is a PTP hardware clock chrony obtains the time from
+type ChronyRefClock struct {
+	// Device Path of the PTP hardware clock device, e.g. /dev/ptp_hyperv
+	Device string
+	// Poll Interval of the clock updates, as power of two in seconds
+	Poll *int32
+	// DPoll Interval of the samples of the device, as power of two in seconds
+	DPoll *int32
+}
+
+// ChronyMakeStep configures when chrony steps the clock
+type ChronyMakeStep struct {
+	// Threshold Offset above which the clock is stepped
+	Threshold metav1.Duration
+	// Limit Number of updates in which the clock may be stepped, -1 for no limit
+	Limit int32
+}
+
+// ContainerdConfig contains the settings rendered into /etc/containerd/config.toml
+type ContainerdConfig struct {
+	// ConfigVersion Version of the containerd configuration file, either 2 or 3. Version 3 requires containerd 2.x.
+	// Defaults to 2, which is understood by containerd 1.7 and 2.x.
+	ConfigVersion *int32
+	// SandboxImage Image used for the pod sandbox until gardener-node-agent configures the one of the shoot
+	SandboxImage *string
+	// Snapshotter Snapshotter used by the CRI plugin. Defaults to overlayfs.
+	Snapshotter *string
+	// RegistryAuth Credentials containerd uses to pull images from private registries. The credentials of a shoot are
+	// merged with the ones of the extension config by registry. They require config version 3, i.e. containerd 2.x.
+	RegistryAuth []RegistryAuth
+}
+
+// RegistryAuth references the credentials of a registry
+type RegistryAuth struct {
+	// Registry Host of the registry, optionally including the port, e.g. registry.example.com:5000
+	Registry string
+	// SecretRef Reference to a Secret in the namespace of the OperatingSystemConfig. The Secret either is of type
+	// kubernetes.io/dockerconfigjson and contains an entry for the registry, or it contains the keys username and
+	// password, auth or identitytoken. The provider config of a shoot may only reference the resources of the shoot,
+	// whose names have the prefix ref-.
+	SecretRef corev1.LocalObjectReference
+}
+
+// SysctlProfile is the name of a predefined set of kernel parameters.
+type SysctlProfile string
+
+const (
+	// SysctlProfileNetworkHeavy tunes the network stack for nodes with many connections and high throughput.
+	SysctlProfileNetworkHeavy SysctlProfile = "network-heavy"
+	// SysctlProfileHardened restricts kernel features which are commonly used in exploits.
+	SysctlProfileHardened SysctlProfile = "hardened"
+)
+
+// SysctlConfig contains the kernel parameters written to /etc/sysctl.d
+type SysctlConfig struct {
+	// Profiles List of predefined sets of kernel parameters, applied in the given order
+	Profiles []SysctlProfile
+	// Settings Kernel parameters applied on top of the profiles. Keys must start with net., vm., kernel. or fs.
+	Settings map[string]string
+}
@@ -23,3 +23,16 @@ func SetDefaults_NTPConfig(obj *NTPConfig) {
 		obj.Enabled = ptr.To(true)
 	}
 }
+
+// SetDefaults_TypeOverride does not default the settings of the override, which would otherwise overwrite the ones of
+// the extension config when merging.
+// +k8s:defaulter-gen=covers
+func SetDefaults_TypeOverride(_ *TypeOverride) {}
+
+// SetDefaults_WorkerPoolOverride does not default the settings of the override for the same reason.
+// +k8s:defaulter-gen=covers
+func SetDefaults_WorkerPoolOverride(_ *WorkerPoolOverride) {}
+
+// SetDefaults_ConfigProfile does not default the settings of the profile for the same reason.
+// +k8s:defaulter-gen=covers
+func SetDefaults_ConfigProfile(_ *ConfigProfile) {}
@@ -0,0 +1,747 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	unsafe "unsafe"
+
+	config "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*AllowedValues)(nil), (*config.AllowedValues)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_AllowedValues_To_config_AllowedValues(a.(*AllowedValues), b.(*config.AllowedValues), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.AllowedValues)(nil), (*AllowedValues)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_AllowedValues_To_v1alpha1_AllowedValues(a.(*config.AllowedValues), b.(*AllowedValues), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ChronyConfig)(nil), (*config.ChronyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ChronyConfig_To_config_ChronyConfig(a.(*ChronyConfig), b.(*config.ChronyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ChronyConfig)(nil), (*ChronyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ChronyConfig_To_v1alpha1_ChronyConfig(a.(*config.ChronyConfig), b.(*ChronyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ChronyMakeStep)(nil), (*config.ChronyMakeStep)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ChronyMakeStep_To_config_ChronyMakeStep(a.(*ChronyMakeStep), b.(*config.ChronyMakeStep), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ChronyMakeStep)(nil), (*ChronyMakeStep)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ChronyMakeStep_To_v1alpha1_ChronyMakeStep(a.(*config.ChronyMakeStep), b.(*ChronyMakeStep), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ChronyRefClock)(nil), (*config.ChronyRefClock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ChronyRefClock_To_config_ChronyRefClock(a.(*ChronyRefClock), b.(*config.ChronyRefClock), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ChronyRefClock)(nil), (*ChronyRefClock)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ChronyRefClock_To_v1alpha1_ChronyRefClock(a.(*config.ChronyRefClock), b.(*ChronyRefClock), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ChronySource)(nil), (*config.ChronySource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ChronySource_To_config_ChronySource(a.(*ChronySource), b.(*config.ChronySource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ChronySource)(nil), (*ChronySource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ChronySource_To_v1alpha1_ChronySource(a.(*config.ChronySource), b.(*ChronySource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ConfigProfile)(nil), (*config.ConfigProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ConfigProfile_To_config_ConfigProfile(a.(*ConfigProfile), b.(*config.ConfigProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ConfigProfile)(nil), (*ConfigProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ConfigProfile_To_v1alpha1_ConfigProfile(a.(*config.ConfigProfile), b.(*ConfigProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdConfig)(nil), (*config.ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ContainerdConfig_To_config_ContainerdConfig(a.(*ContainerdConfig), b.(*config.ContainerdConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ContainerdConfig)(nil), (*ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ContainerdConfig_To_v1alpha1_ContainerdConfig(a.(*config.ContainerdConfig), b.(*ContainerdConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ExtensionConfig)(nil), (*config.ExtensionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig(a.(*ExtensionConfig), b.(*config.ExtensionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ExtensionConfig)(nil), (*ExtensionConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig(a.(*config.ExtensionConfig), b.(*ExtensionConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageVersionRule)(nil), (*config.ImageVersionRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ImageVersionRule_To_config_ImageVersionRule(a.(*ImageVersionRule), b.(*config.ImageVersionRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ImageVersionRule)(nil), (*ImageVersionRule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ImageVersionRule_To_v1alpha1_ImageVersionRule(a.(*config.ImageVersionRule), b.(*ImageVersionRule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NTPConfig)(nil), (*config.NTPConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NTPConfig_To_config_NTPConfig(a.(*NTPConfig), b.(*config.NTPConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NTPConfig)(nil), (*NTPConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NTPConfig_To_v1alpha1_NTPConfig(a.(*config.NTPConfig), b.(*NTPConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NTPDAuthentication)(nil), (*config.NTPDAuthentication)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NTPDAuthentication_To_config_NTPDAuthentication(a.(*NTPDAuthentication), b.(*config.NTPDAuthentication), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NTPDAuthentication)(nil), (*NTPDAuthentication)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NTPDAuthentication_To_v1alpha1_NTPDAuthentication(a.(*config.NTPDAuthentication), b.(*NTPDAuthentication), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NTPDConfig)(nil), (*config.NTPDConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NTPDConfig_To_config_NTPDConfig(a.(*NTPDConfig), b.(*config.NTPDConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NTPDConfig)(nil), (*NTPDConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NTPDConfig_To_v1alpha1_NTPDConfig(a.(*config.NTPDConfig), b.(*NTPDConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NTPDSource)(nil), (*config.NTPDSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NTPDSource_To_config_NTPDSource(a.(*NTPDSource), b.(*config.NTPDSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NTPDSource)(nil), (*NTPDSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NTPDSource_To_v1alpha1_NTPDSource(a.(*config.NTPDSource), b.(*NTPDSource), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NTPWaitForSync)(nil), (*config.NTPWaitForSync)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NTPWaitForSync_To_config_NTPWaitForSync(a.(*NTPWaitForSync), b.(*config.NTPWaitForSync), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NTPWaitForSync)(nil), (*NTPWaitForSync)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NTPWaitForSync_To_v1alpha1_NTPWaitForSync(a.(*config.NTPWaitForSync), b.(*NTPWaitForSync), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PolicyConfig)(nil), (*config.PolicyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PolicyConfig_To_config_PolicyConfig(a.(*PolicyConfig), b.(*config.PolicyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PolicyConfig)(nil), (*PolicyConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PolicyConfig_To_v1alpha1_PolicyConfig(a.(*config.PolicyConfig), b.(*PolicyConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RegistryAuth)(nil), (*config.RegistryAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RegistryAuth_To_config_RegistryAuth(a.(*RegistryAuth), b.(*config.RegistryAuth), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RegistryAuth)(nil), (*RegistryAuth)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RegistryAuth_To_v1alpha1_RegistryAuth(a.(*config.RegistryAuth), b.(*RegistryAuth), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SysctlConfig)(nil), (*config.SysctlConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SysctlConfig_To_config_SysctlConfig(a.(*SysctlConfig), b.(*config.SysctlConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SysctlConfig)(nil), (*SysctlConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SysctlConfig_To_v1alpha1_SysctlConfig(a.(*config.SysctlConfig), b.(*SysctlConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TimesyncdConfig)(nil), (*config.TimesyncdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TimesyncdConfig_To_config_TimesyncdConfig(a.(*TimesyncdConfig), b.(*config.TimesyncdConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TimesyncdConfig)(nil), (*TimesyncdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TimesyncdConfig_To_v1alpha1_TimesyncdConfig(a.(*config.TimesyncdConfig), b.(*TimesyncdConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TypeOverride)(nil), (*config.TypeOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TypeOverride_To_config_TypeOverride(a.(*TypeOverride), b.(*config.TypeOverride), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TypeOverride)(nil), (*TypeOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TypeOverride_To_v1alpha1_TypeOverride(a.(*config.TypeOverride), b.(*TypeOverride), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*WorkerPoolOverride)(nil), (*config.WorkerPoolOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_WorkerPoolOverride_To_config_WorkerPoolOverride(a.(*WorkerPoolOverride), b.(*config.WorkerPoolOverride), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.WorkerPoolOverride)(nil), (*WorkerPoolOverride)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_WorkerPoolOverride_To_v1alpha1_WorkerPoolOverride(a.(*config.WorkerPoolOverride), b.(*WorkerPoolOverride), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_AllowedValues_To_config_AllowedValues(in *AllowedValues, out *config.AllowedValues, s conversion.Scope) error {
+	out.Field = in.Field
+	out.Values = *(*[]string)(unsafe.Pointer(&in.Values))
+	return nil
+}
+
+// Convert_v1alpha1_AllowedValues_To_config_AllowedValues is an autogenerated conversion function.
+func Convert_v1alpha1_AllowedValues_To_config_AllowedValues(in *AllowedValues, out *config.AllowedValues, s conversion.Scope) error {
+	return autoConvert_v1alpha1_AllowedValues_To_config_AllowedValues(in, out, s)
+}
+
+func autoConvert_config_AllowedValues_To_v1alpha1_AllowedValues(in *config.AllowedValues, out *AllowedValues, s conversion.Scope) error {
+	out.Field = in.Field
+	out.Values = *(*[]string)(unsafe.Pointer(&in.Values))
+	return nil
+}
+
+// Convert_config_AllowedValues_To_v1alpha1_AllowedValues is an autogenerated conversion function.
+func Convert_config_AllowedValues_To_v1alpha1_AllowedValues(in *config.AllowedValues, out *AllowedValues, s conversion.Scope) error {
+	return autoConvert_config_AllowedValues_To_v1alpha1_AllowedValues(in, out, s)
+}
+
+func autoConvert_v1alpha1_ChronyConfig_To_config_ChronyConfig(in *ChronyConfig, out *config.ChronyConfig, s conversion.Scope) error {
+	out.Servers = *(*[]config.ChronySource)(unsafe.Pointer(&in.Servers))
+	out.Pools = *(*[]config.ChronySource)(unsafe.Pointer(&in.Pools))
+	out.RefClocks = *(*[]config.ChronyRefClock)(unsafe.Pointer(&in.RefClocks))
+	out.MakeStep = (*config.ChronyMakeStep)(unsafe.Pointer(in.MakeStep))
+	out.RTCSync = (*bool)(unsafe.Pointer(in.RTCSync))
+	out.Allow = *(*[]string)(unsafe.Pointer(&in.Allow))
+	out.NTSTrustedCertificates = (*string)(unsafe.Pointer(in.NTSTrustedCertificates))
+	return nil
+}
+
+// Convert_v1alpha1_ChronyConfig_To_config_ChronyConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ChronyConfig_To_config_ChronyConfig(in *ChronyConfig, out *config.ChronyConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ChronyConfig_To_config_ChronyConfig(in, out, s)
+}
+
+func autoConvert_config_ChronyConfig_To_v1alpha1_ChronyConfig(in *config.ChronyConfig, out *ChronyConfig, s conversion.Scope) error {
+	out.Servers = *(*[]ChronySource)(unsafe.Pointer(&in.Servers))
+	out.Pools = *(*[]ChronySource)(unsafe.Pointer(&in.Pools))
+	out.RefClocks = *(*[]ChronyRefClock)(unsafe.Pointer(&in.RefClocks))
+	out.MakeStep = (*ChronyMakeStep)(unsafe.Pointer(in.MakeStep))
+	out.RTCSync = (*bool)(unsafe.Pointer(in.RTCSync))
+	out.Allow = *(*[]string)(unsafe.Pointer(&in.Allow))
+	out.NTSTrustedCertificates = (*string)(unsafe.Pointer(in.NTSTrustedCertificates))
+	return nil
+}
+
+// Convert_config_ChronyConfig_To_v1alpha1_ChronyConfig is an autogenerated conversion function.
+func Convert_config_ChronyConfig_To_v1alpha1_ChronyConfig(in *config.ChronyConfig, out *ChronyConfig, s conversion.Scope) error {
+	return autoConvert_config_ChronyConfig_To_v1alpha1_ChronyConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_ChronyMakeStep_To_config_ChronyMakeStep(in *ChronyMakeStep, out *config.ChronyMakeStep, s conversion.Scope) error {
+	out.Threshold = in.Threshold
+	out.Limit = in.Limit
+	return nil
+}
+
+// Convert_v1alpha1_ChronyMakeStep_To_config_ChronyMakeStep is an autogenerated conversion function.
+func Convert_v1alpha1_ChronyMakeStep_To_config_ChronyMakeStep(in *ChronyMakeStep, out *config.ChronyMakeStep, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ChronyMakeStep_To_config_ChronyMakeStep(in, out, s)
+}
+
+func autoConvert_config_ChronyMakeStep_To_v1alpha1_ChronyMakeStep(in *config.ChronyMakeStep, out *ChronyMakeStep, s conversion.Scope) error {
+	out.Threshold = in.Threshold
+	out.Limit = in.Limit
+	return nil
+}
+
+// Convert_config_ChronyMakeStep_To_v1alpha1_ChronyMakeStep is an autogenerated conversion function.
+func Convert_config_ChronyMakeStep_To_v1alpha1_ChronyMakeStep(in *config.ChronyMakeStep, out *ChronyMakeStep, s conversion.Scope) error {
+	return autoConvert_config_ChronyMakeStep_To_v1alpha1_ChronyMakeStep(in, out, s)
+}
+
+func autoConvert_v1alpha1_ChronyRefClock_To_config_ChronyRefClock(in *ChronyRefClock, out *config.ChronyRefClock, s conversion.Scope) error {
+	out.Device = in.Device
+	out.Poll = (*int32)(unsafe.Pointer(in.Poll))
+	out.DPoll = (*int32)(unsafe.Pointer(in.DPoll))
+	return nil
+}
+
+// Convert_v1alpha1_ChronyRefClock_To_config_ChronyRefClock is an autogenerated conversion function.
+func Convert_v1alpha1_ChronyRefClock_To_config_ChronyRefClock(in *ChronyRefClock, out *config.ChronyRefClock, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ChronyRefClock_To_config_ChronyRefClock(in, out, s)
+}
+
+func autoConvert_config_ChronyRefClock_To_v1alpha1_ChronyRefClock(in *config.ChronyRefClock, out *ChronyRefClock, s conversion.Scope) error {
+	out.Device = in.Device
+	out.Poll = (*int32)(unsafe.Pointer(in.Poll))
+	out.DPoll = (*int32)(unsafe.Pointer(in.DPoll))
+	return nil
+}
+
+// Convert_config_ChronyRefClock_To_v1alpha1_ChronyRefClock is an autogenerated conversion function.
+func Convert_config_ChronyRefClock_To_v1alpha1_ChronyRefClock(in *config.ChronyRefClock, out *ChronyRefClock, s conversion.Scope) error {
+	return autoConvert_config_ChronyRefClock_To_v1alpha1_ChronyRefClock(in, out, s)
+}
+
+func autoConvert_v1alpha1_ChronySource_To_config_ChronySource(in *ChronySource, out *config.ChronySource, s conversion.Scope) error {
+	out.Address = in.Address
+	out.NTS = in.NTS
+	return nil
+}
+
+// Convert_v1alpha1_ChronySource_To_config_ChronySource is an autogenerated conversion function.
+func Convert_v1alpha1_ChronySource_To_config_ChronySource(in *ChronySource, out *config.ChronySource, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ChronySource_To_config_ChronySource(in, out, s)
+}
+
+func autoConvert_config_ChronySource_To_v1alpha1_ChronySource(in *config.ChronySource, out *ChronySource, s conversion.Scope) error {
+	out.Address = in.Address
+	out.NTS = in.NTS
+	return nil
+}
+
+// Convert_config_ChronySource_To_v1alpha1_ChronySource is an autogenerated conversion function.
+func Convert_config_ChronySource_To_v1alpha1_ChronySource(in *config.ChronySource, out *ChronySource, s conversion.Scope) error {
+	return autoConvert_config_ChronySource_To_v1alpha1_ChronySource(in, out, s)
+}
+
+func autoConvert_v1alpha1_ConfigProfile_To_config_ConfigProfile(in *ConfigProfile, out *config.ConfigProfile, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_ConfigProfile_To_config_ConfigProfile is an autogenerated conversion function.
+func Convert_v1alpha1_ConfigProfile_To_config_ConfigProfile(in *ConfigProfile, out *config.ConfigProfile, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ConfigProfile_To_config_ConfigProfile(in, out, s)
+}
+
+func autoConvert_config_ConfigProfile_To_v1alpha1_ConfigProfile(in *config.ConfigProfile, out *ConfigProfile, s conversion.Scope) error {
+	out.Name = in.Name
+	if err := Convert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_ConfigProfile_To_v1alpha1_ConfigProfile is an autogenerated conversion function.
+func Convert_config_ConfigProfile_To_v1alpha1_ConfigProfile(in *config.ConfigProfile, out *ConfigProfile, s conversion.Scope) error {
+	return autoConvert_config_ConfigProfile_To_v1alpha1_ConfigProfile(in, out, s)
+}
+
+func autoConvert_v1alpha1_ContainerdConfig_To_config_ContainerdConfig(in *ContainerdConfig, out *config.ContainerdConfig, s conversion.Scope) error {
+	out.ConfigVersion = (*int32)(unsafe.Pointer(in.ConfigVersion))
+	out.SandboxImage = (*string)(unsafe.Pointer(in.SandboxImage))
+	out.Snapshotter = (*string)(unsafe.Pointer(in.Snapshotter))
+	out.RegistryAuth = *(*[]config.RegistryAuth)(unsafe.Pointer(&in.RegistryAuth))
+	return nil
+}
+
+// Convert_v1alpha1_ContainerdConfig_To_config_ContainerdConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ContainerdConfig_To_config_ContainerdConfig(in *ContainerdConfig, out *config.ContainerdConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ContainerdConfig_To_config_ContainerdConfig(in, out, s)
+}
+
+func autoConvert_config_ContainerdConfig_To_v1alpha1_ContainerdConfig(in *config.ContainerdConfig, out *ContainerdConfig, s conversion.Scope) error {
+	out.ConfigVersion = (*int32)(unsafe.Pointer(in.ConfigVersion))
+	out.SandboxImage = (*string)(unsafe.Pointer(in.SandboxImage))
+	out.Snapshotter = (*string)(unsafe.Pointer(in.Snapshotter))
+	out.RegistryAuth = *(*[]RegistryAuth)(unsafe.Pointer(&in.RegistryAuth))
+	return nil
+}
+
+// Convert_config_ContainerdConfig_To_v1alpha1_ContainerdConfig is an autogenerated conversion function.
+func Convert_config_ContainerdConfig_To_v1alpha1_ContainerdConfig(in *config.ContainerdConfig, out *ContainerdConfig, s conversion.Scope) error {
+	return autoConvert_config_ContainerdConfig_To_v1alpha1_ContainerdConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig(in *ExtensionConfig, out *config.ExtensionConfig, s conversion.Scope) error {
+	out.EnableDocker = (*bool)(unsafe.Pointer(in.EnableDocker))
+	out.NTP = (*config.NTPConfig)(unsafe.Pointer(in.NTP))
+	out.Sysctl = (*config.SysctlConfig)(unsafe.Pointer(in.Sysctl))
+	out.Containerd = (*config.ContainerdConfig)(unsafe.Pointer(in.Containerd))
+	out.Policy = (*config.PolicyConfig)(unsafe.Pointer(in.Policy))
+	out.TypeOverrides = *(*[]config.TypeOverride)(unsafe.Pointer(&in.TypeOverrides))
+	out.ImageVersionRules = *(*[]config.ImageVersionRule)(unsafe.Pointer(&in.ImageVersionRules))
+	out.WorkerPoolOverrides = *(*[]config.WorkerPoolOverride)(unsafe.Pointer(&in.WorkerPoolOverrides))
+	out.Profiles = *(*[]config.ConfigProfile)(unsafe.Pointer(&in.Profiles))
+	out.Profile = in.Profile
+	return nil
+}
+
+// Convert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig(in *ExtensionConfig, out *config.ExtensionConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig(in, out, s)
+}
+
+func autoConvert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig(in *config.ExtensionConfig, out *ExtensionConfig, s conversion.Scope) error {
+	out.EnableDocker = (*bool)(unsafe.Pointer(in.EnableDocker))
+	out.NTP = (*NTPConfig)(unsafe.Pointer(in.NTP))
+	out.Sysctl = (*SysctlConfig)(unsafe.Pointer(in.Sysctl))
+	out.Containerd = (*ContainerdConfig)(unsafe.Pointer(in.Containerd))
+	out.Policy = (*PolicyConfig)(unsafe.Pointer(in.Policy))
+	out.TypeOverrides = *(*[]TypeOverride)(unsafe.Pointer(&in.TypeOverrides))
+	out.ImageVersionRules = *(*[]ImageVersionRule)(unsafe.Pointer(&in.ImageVersionRules))
+	out.WorkerPoolOverrides = *(*[]WorkerPoolOverride)(unsafe.Pointer(&in.WorkerPoolOverrides))
+	out.Profiles = *(*[]ConfigProfile)(unsafe.Pointer(&in.Profiles))
+	out.Profile = in.Profile
+	return nil
+}
+
+// Convert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig is an autogenerated conversion function.
+func Convert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig(in *config.ExtensionConfig, out *ExtensionConfig, s conversion.Scope) error {
+	return autoConvert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_ImageVersionRule_To_config_ImageVersionRule(in *ImageVersionRule, out *config.ImageVersionRule, s conversion.Scope) error {
+	out.Versions = in.Versions
+	out.Enable = *(*[]config.Customization)(unsafe.Pointer(&in.Enable))
+	out.Disable = *(*[]config.Customization)(unsafe.Pointer(&in.Disable))
+	return nil
+}
+
+// Convert_v1alpha1_ImageVersionRule_To_config_ImageVersionRule is an autogenerated conversion function.
+func Convert_v1alpha1_ImageVersionRule_To_config_ImageVersionRule(in *ImageVersionRule, out *config.ImageVersionRule, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ImageVersionRule_To_config_ImageVersionRule(in, out, s)
+}
+
+func autoConvert_config_ImageVersionRule_To_v1alpha1_ImageVersionRule(in *config.ImageVersionRule, out *ImageVersionRule, s conversion.Scope) error {
+	out.Versions = in.Versions
+	out.Enable = *(*[]Customization)(unsafe.Pointer(&in.Enable))
+	out.Disable = *(*[]Customization)(unsafe.Pointer(&in.Disable))
+	return nil
+}
+
+// Convert_config_ImageVersionRule_To_v1alpha1_ImageVersionRule is an autogenerated conversion function.
+func Convert_config_ImageVersionRule_To_v1alpha1_ImageVersionRule(in *config.ImageVersionRule, out *ImageVersionRule, s conversion.Scope) error {
+	return autoConvert_config_ImageVersionRule_To_v1alpha1_ImageVersionRule(in, out, s)
+}
+
+func autoConvert_v1alpha1_NTPConfig_To_config_NTPConfig(in *NTPConfig, out *config.NTPConfig, s conversion.Scope) error {
+	out.Enabled = (*bool)(unsafe.Pointer(in.Enabled))
+	out.Daemon = config.Daemon(in.Daemon)
+	out.NTPD = (*config.NTPDConfig)(unsafe.Pointer(in.NTPD))
+	out.Timesyncd = (*config.TimesyncdConfig)(unsafe.Pointer(in.Timesyncd))
+	out.Chrony = (*config.ChronyConfig)(unsafe.Pointer(in.Chrony))
+	out.RequireAuthentication = (*bool)(unsafe.Pointer(in.RequireAuthentication))
+	out.WaitForSync = (*config.NTPWaitForSync)(unsafe.Pointer(in.WaitForSync))
+	return nil
+}
+
+// Convert_v1alpha1_NTPConfig_To_config_NTPConfig is an autogenerated conversion function.
+func Convert_v1alpha1_NTPConfig_To_config_NTPConfig(in *NTPConfig, out *config.NTPConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NTPConfig_To_config_NTPConfig(in, out, s)
+}
+
+func autoConvert_config_NTPConfig_To_v1alpha1_NTPConfig(in *config.NTPConfig, out *NTPConfig, s conversion.Scope) error {
+	out.Enabled = (*bool)(unsafe.Pointer(in.Enabled))
+	out.Daemon = Daemon(in.Daemon)
+	out.NTPD = (*NTPDConfig)(unsafe.Pointer(in.NTPD))
+	out.Timesyncd = (*TimesyncdConfig)(unsafe.Pointer(in.Timesyncd))
+	out.Chrony = (*ChronyConfig)(unsafe.Pointer(in.Chrony))
+	out.RequireAuthentication = (*bool)(unsafe.Pointer(in.RequireAuthentication))
+	out.WaitForSync = (*NTPWaitForSync)(unsafe.Pointer(in.WaitForSync))
+	return nil
+}
+
+// Convert_config_NTPConfig_To_v1alpha1_NTPConfig is an autogenerated conversion function.
+func Convert_config_NTPConfig_To_v1alpha1_NTPConfig(in *config.NTPConfig, out *NTPConfig, s conversion.Scope) error {
+	return autoConvert_config_NTPConfig_To_v1alpha1_NTPConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_NTPDAuthentication_To_config_NTPDAuthentication(in *NTPDAuthentication, out *config.NTPDAuthentication, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Type = in.Type
+	out.SecretRef = in.SecretRef
+	return nil
+}
+
+// Convert_v1alpha1_NTPDAuthentication_To_config_NTPDAuthentication is an autogenerated conversion function.
+func Convert_v1alpha1_NTPDAuthentication_To_config_NTPDAuthentication(in *NTPDAuthentication, out *config.NTPDAuthentication, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NTPDAuthentication_To_config_NTPDAuthentication(in, out, s)
+}
+
+func autoConvert_config_NTPDAuthentication_To_v1alpha1_NTPDAuthentication(in *config.NTPDAuthentication, out *NTPDAuthentication, s conversion.Scope) error {
+	out.KeyID = in.KeyID
+	out.Type = in.Type
+	out.SecretRef = in.SecretRef
+	return nil
+}
+
+// Convert_config_NTPDAuthentication_To_v1alpha1_NTPDAuthentication is an autogenerated conversion function.
+func Convert_config_NTPDAuthentication_To_v1alpha1_NTPDAuthentication(in *config.NTPDAuthentication, out *NTPDAuthentication, s conversion.Scope) error {
+	return autoConvert_config_NTPDAuthentication_To_v1alpha1_NTPDAuthentication(in, out, s)
+}
+
+func autoConvert_v1alpha1_NTPDConfig_To_config_NTPDConfig(in *NTPDConfig, out *config.NTPDConfig, s conversion.Scope) error {
+	out.Servers = *(*[]string)(unsafe.Pointer(&in.Servers))
+	out.Sources = *(*[]config.NTPDSource)(unsafe.Pointer(&in.Sources))
+	out.Interfaces = *(*[]string)(unsafe.Pointer(&in.Interfaces))
+	out.Authentication = (*config.NTPDAuthentication)(unsafe.Pointer(in.Authentication))
+	out.Restrict = *(*[]string)(unsafe.Pointer(&in.Restrict))
+	out.DriftFile = (*string)(unsafe.Pointer(in.DriftFile))
+	return nil
+}
+
+// Convert_v1alpha1_NTPDConfig_To_config_NTPDConfig is an autogenerated conversion function.
+func Convert_v1alpha1_NTPDConfig_To_config_NTPDConfig(in *NTPDConfig, out *config.NTPDConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NTPDConfig_To_config_NTPDConfig(in, out, s)
+}
+
+func autoConvert_config_NTPDConfig_To_v1alpha1_NTPDConfig(in *config.NTPDConfig, out *NTPDConfig, s conversion.Scope) error {
+	out.Servers = *(*[]string)(unsafe.Pointer(&in.Servers))
+	out.Sources = *(*[]NTPDSource)(unsafe.Pointer(&in.Sources))
+	out.Interfaces = *(*[]string)(unsafe.Pointer(&in.Interfaces))
+	out.Authentication = (*NTPDAuthentication)(unsafe.Pointer(in.Authentication))
+	out.Restrict = *(*[]string)(unsafe.Pointer(&in.Restrict))
+	out.DriftFile = (*string)(unsafe.Pointer(in.DriftFile))
+	return nil
+}
+
+// Convert_config_NTPDConfig_To_v1alpha1_NTPDConfig is an autogenerated conversion function.
+func Convert_config_NTPDConfig_To_v1alpha1_NTPDConfig(in *config.NTPDConfig, out *NTPDConfig, s conversion.Scope) error {
+	return autoConvert_config_NTPDConfig_To_v1alpha1_NTPDConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_NTPDSource_To_config_NTPDSource(in *NTPDSource, out *config.NTPDSource, s conversion.Scope) error {
+	out.Address = in.Address
+	out.Pool = in.Pool
+	out.Prefer = in.Prefer
+	out.MinPoll = (*int32)(unsafe.Pointer(in.MinPoll))
+	out.MaxPoll = (*int32)(unsafe.Pointer(in.MaxPoll))
+	out.Key = (*int32)(unsafe.Pointer(in.Key))
+	return nil
+}
+
+// Convert_v1alpha1_NTPDSource_To_config_NTPDSource is an autogenerated conversion function.
+func Convert_v1alpha1_NTPDSource_To_config_NTPDSource(in *NTPDSource, out *config.NTPDSource, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NTPDSource_To_config_NTPDSource(in, out, s)
+}
+
+func autoConvert_config_NTPDSource_To_v1alpha1_NTPDSource(in *config.NTPDSource, out *NTPDSource, s conversion.Scope) error {
+	out.Address = in.Address
+	out.Pool = in.Pool
+	out.Prefer = in.Prefer
+	out.MinPoll = (*int32)(unsafe.Pointer(in.MinPoll))
+	out.MaxPoll = (*int32)(unsafe.Pointer(in.MaxPoll))
+	out.Key = (*int32)(unsafe.Pointer(in.Key))
+	return nil
+}
+
+// Convert_config_NTPDSource_To_v1alpha1_NTPDSource is an autogenerated conversion function.
+func Convert_config_NTPDSource_To_v1alpha1_NTPDSource(in *config.NTPDSource, out *NTPDSource, s conversion.Scope) error {
+	return autoConvert_config_NTPDSource_To_v1alpha1_NTPDSource(in, out, s)
+}
+
+func autoConvert_v1alpha1_NTPWaitForSync_To_config_NTPWaitForSync(in *NTPWaitForSync, out *config.NTPWaitForSync, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_v1alpha1_NTPWaitForSync_To_config_NTPWaitForSync is an autogenerated conversion function.
+func Convert_v1alpha1_NTPWaitForSync_To_config_NTPWaitForSync(in *NTPWaitForSync, out *config.NTPWaitForSync, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NTPWaitForSync_To_config_NTPWaitForSync(in, out, s)
+}
+
+func autoConvert_config_NTPWaitForSync_To_v1alpha1_NTPWaitForSync(in *config.NTPWaitForSync, out *NTPWaitForSync, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.Timeout = (*v1.Duration)(unsafe.Pointer(in.Timeout))
+	return nil
+}
+
+// Convert_config_NTPWaitForSync_To_v1alpha1_NTPWaitForSync is an autogenerated conversion function.
+func Convert_config_NTPWaitForSync_To_v1alpha1_NTPWaitForSync(in *config.NTPWaitForSync, out *NTPWaitForSync, s conversion.Scope) error {
+	return autoConvert_config_NTPWaitForSync_To_v1alpha1_NTPWaitForSync(in, out, s)
+}
+
+func autoConvert_v1alpha1_PolicyConfig_To_config_PolicyConfig(in *PolicyConfig, out *config.PolicyConfig, s conversion.Scope) error {
+	out.LockedFields = *(*[]string)(unsafe.Pointer(&in.LockedFields))
+	out.AllowedValues = *(*[]config.AllowedValues)(unsafe.Pointer(&in.AllowedValues))
+	return nil
+}
+
+// Convert_v1alpha1_PolicyConfig_To_config_PolicyConfig is an autogenerated conversion function.
+func Convert_v1alpha1_PolicyConfig_To_config_PolicyConfig(in *PolicyConfig, out *config.PolicyConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PolicyConfig_To_config_PolicyConfig(in, out, s)
+}
+
+func autoConvert_config_PolicyConfig_To_v1alpha1_PolicyConfig(in *config.PolicyConfig, out *PolicyConfig, s conversion.Scope) error {
+	out.LockedFields = *(*[]string)(unsafe.Pointer(&in.LockedFields))
+	out.AllowedValues = *(*[]AllowedValues)(unsafe.Pointer(&in.AllowedValues))
+	return nil
+}
+
+// Convert_config_PolicyConfig_To_v1alpha1_PolicyConfig is an autogenerated conversion function.
+func Convert_config_PolicyConfig_To_v1alpha1_PolicyConfig(in *config.PolicyConfig, out *PolicyConfig, s conversion.Scope) error {
+	return autoConvert_config_PolicyConfig_To_v1alpha1_PolicyConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_RegistryAuth_To_config_RegistryAuth(in *RegistryAuth, out *config.RegistryAuth, s conversion.Scope) error {
+	out.Registry = in.Registry
+	out.SecretRef = in.SecretRef
+	return nil
+}
+
+// Convert_v1alpha1_RegistryAuth_To_config_RegistryAuth is an autogenerated conversion function.
+func Convert_v1alpha1_RegistryAuth_To_config_RegistryAuth(in *RegistryAuth, out *config.RegistryAuth, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RegistryAuth_To_config_RegistryAuth(in, out, s)
+}
+
+func autoConvert_config_RegistryAuth_To_v1alpha1_RegistryAuth(in *config.RegistryAuth, out *RegistryAuth, s conversion.Scope) error {
+	out.Registry = in.Registry
+	out.SecretRef = in.SecretRef
+	return nil
+}
+
+// Convert_config_RegistryAuth_To_v1alpha1_RegistryAuth is an autogenerated conversion function.
+func Convert_config_RegistryAuth_To_v1alpha1_RegistryAuth(in *config.RegistryAuth, out *RegistryAuth, s conversion.Scope) error {
+	return autoConvert_config_RegistryAuth_To_v1alpha1_RegistryAuth(in, out, s)
+}
+
+func autoConvert_v1alpha1_SysctlConfig_To_config_SysctlConfig(in *SysctlConfig, out *config.SysctlConfig, s conversion.Scope) error {
+	out.Profiles = *(*[]config.SysctlProfile)(unsafe.Pointer(&in.Profiles))
+	out.Settings = *(*map[string]string)(unsafe.Pointer(&in.Settings))
+	return nil
+}
+
+// Convert_v1alpha1_SysctlConfig_To_config_SysctlConfig is an autogenerated conversion function.
+func Convert_v1alpha1_SysctlConfig_To_config_SysctlConfig(in *SysctlConfig, out *config.SysctlConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SysctlConfig_To_config_SysctlConfig(in, out, s)
+}
+
+func autoConvert_config_SysctlConfig_To_v1alpha1_SysctlConfig(in *config.SysctlConfig, out *SysctlConfig, s conversion.Scope) error {
+	out.Profiles = *(*[]SysctlProfile)(unsafe.Pointer(&in.Profiles))
+	out.Settings = *(*map[string]string)(unsafe.Pointer(&in.Settings))
+	return nil
+}
+
+// Convert_config_SysctlConfig_To_v1alpha1_SysctlConfig is an autogenerated conversion function.
+func Convert_config_SysctlConfig_To_v1alpha1_SysctlConfig(in *config.SysctlConfig, out *SysctlConfig, s conversion.Scope) error {
+	return autoConvert_config_SysctlConfig_To_v1alpha1_SysctlConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_TimesyncdConfig_To_config_TimesyncdConfig(in *TimesyncdConfig, out *config.TimesyncdConfig, s conversion.Scope) error {
+	out.Servers = *(*[]string)(unsafe.Pointer(&in.Servers))
+	out.FallbackServers = *(*[]string)(unsafe.Pointer(&in.FallbackServers))
+	out.PollIntervalMin = (*v1.Duration)(unsafe.Pointer(in.PollIntervalMin))
+	out.PollIntervalMax = (*v1.Duration)(unsafe.Pointer(in.PollIntervalMax))
+	return nil
+}
+
+// Convert_v1alpha1_TimesyncdConfig_To_config_TimesyncdConfig is an autogenerated conversion function.
+func Convert_v1alpha1_TimesyncdConfig_To_config_TimesyncdConfig(in *TimesyncdConfig, out *config.TimesyncdConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TimesyncdConfig_To_config_TimesyncdConfig(in, out, s)
+}
+
+func autoConvert_config_TimesyncdConfig_To_v1alpha1_TimesyncdConfig(in *config.TimesyncdConfig, out *TimesyncdConfig, s conversion.Scope) error {
+	out.Servers = *(*[]string)(unsafe.Pointer(&in.Servers))
+	out.FallbackServers = *(*[]string)(unsafe.Pointer(&in.FallbackServers))
+	out.PollIntervalMin = (*v1.Duration)(unsafe.Pointer(in.PollIntervalMin))
+	out.PollIntervalMax = (*v1.Duration)(unsafe.Pointer(in.PollIntervalMax))
+	return nil
+}
+
+// Convert_config_TimesyncdConfig_To_v1alpha1_TimesyncdConfig is an autogenerated conversion function.
+func Convert_config_TimesyncdConfig_To_v1alpha1_TimesyncdConfig(in *config.TimesyncdConfig, out *TimesyncdConfig, s conversion.Scope) error {
+	return autoConvert_config_TimesyncdConfig_To_v1alpha1_TimesyncdConfig(in, out, s)
+}
+
+func autoConvert_v1alpha1_TypeOverride_To_config_TypeOverride(in *TypeOverride, out *config.TypeOverride, s conversion.Scope) error {
+	out.Types = *(*[]string)(unsafe.Pointer(&in.Types))
+	if err := Convert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_TypeOverride_To_config_TypeOverride is an autogenerated conversion function.
+func Convert_v1alpha1_TypeOverride_To_config_TypeOverride(in *TypeOverride, out *config.TypeOverride, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TypeOverride_To_config_TypeOverride(in, out, s)
+}
+
+func autoConvert_config_TypeOverride_To_v1alpha1_TypeOverride(in *config.TypeOverride, out *TypeOverride, s conversion.Scope) error {
+	out.Types = *(*[]string)(unsafe.Pointer(&in.Types))
+	if err := Convert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_TypeOverride_To_v1alpha1_TypeOverride is an autogenerated conversion function.
+func Convert_config_TypeOverride_To_v1alpha1_TypeOverride(in *config.TypeOverride, out *TypeOverride, s conversion.Scope) error {
+	return autoConvert_config_TypeOverride_To_v1alpha1_TypeOverride(in, out, s)
+}
+
+func autoConvert_v1alpha1_WorkerPoolOverride_To_config_WorkerPoolOverride(in *WorkerPoolOverride, out *config.WorkerPoolOverride, s conversion.Scope) error {
+	out.Pools = *(*[]string)(unsafe.Pointer(&in.Pools))
+	if err := Convert_v1alpha1_ExtensionConfig_To_config_ExtensionConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_WorkerPoolOverride_To_config_WorkerPoolOverride is an autogenerated conversion function.
+func Convert_v1alpha1_WorkerPoolOverride_To_config_WorkerPoolOverride(in *WorkerPoolOverride, out *config.WorkerPoolOverride, s conversion.Scope) error {
+	return autoConvert_v1alpha1_WorkerPoolOverride_To_config_WorkerPoolOverride(in, out, s)
+}
+
+func autoConvert_config_WorkerPoolOverride_To_v1alpha1_WorkerPoolOverride(in *config.WorkerPoolOverride, out *WorkerPoolOverride, s conversion.Scope) error {
+	out.Pools = *(*[]string)(unsafe.Pointer(&in.Pools))
+	if err := Convert_config_ExtensionConfig_To_v1alpha1_ExtensionConfig(&in.Config, &out.Config, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_WorkerPoolOverride_To_v1alpha1_WorkerPoolOverride is an autogenerated conversion function.
+func Convert_config_WorkerPoolOverride_To_v1alpha1_WorkerPoolOverride(in *config.WorkerPoolOverride, out *WorkerPoolOverride, s conversion.Scope) error {
+	return autoConvert_config_WorkerPoolOverride_To_v1alpha1_WorkerPoolOverride(in, out, s)
+}
@@ -26,4 +26,16 @@ func SetObjectDefaults_ExtensionConfig(in *ExtensionConfig) {
 	if in.NTP != nil {
 		SetDefaults_NTPConfig(in.NTP)
 	}
+	for i := range in.TypeOverrides {
+		a := &in.TypeOverrides[i]
+		SetDefaults_TypeOverride(a)
+	}
+	for i := range in.WorkerPoolOverrides {
+		a := &in.WorkerPoolOverrides[i]
+		SetDefaults_WorkerPoolOverride(a)
+	}
+	for i := range in.Profiles {
+		a := &in.Profiles[i]
+		SetDefaults_ConfigProfile(a)
+	}
 }
@@ -0,0 +1,38 @@
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	return RegisterDefaults(scheme)
+}
+
+func SetDefaults_ExtensionConfig(obj *ExtensionConfig) {
+	if obj.NTP == nil {
+		obj.NTP = &NTPConfig{}
+	}
+}
+
+func SetDefaults_NTPConfig(obj *NTPConfig) {
+	if obj.Daemon == "" {
+		obj.Daemon = SystemdTimesyncd
+	}
+	if obj.Enabled == nil {
+		obj.Enabled = ptr.To(true)
+	}
+}
+
+// SetDefaults_TypeOverride does not default the settings of the override, which would otherwise overwrite the ones of
+// the extension config when merging.
+// +k8s:defaulter-gen=covers
+func SetDefaults_TypeOverride(_ *TypeOverride) {}
+
+// SetDefaults_WorkerPoolOverride does not default the settings of the override for the same reason.
+// +k8s:defaulter-gen=covers
+func SetDefaults_WorkerPoolOverride(_ *WorkerPoolOverride) {}
+
+// SetDefaults_ConfigProfile does not default the settings of the profile for the same reason.
+// +k8s:defaulter-gen=covers
+func SetDefaults_ConfigProfile(_ *ConfigProfile) {}
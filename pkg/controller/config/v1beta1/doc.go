@@ -0,0 +1,12 @@
+// +k8s:deepcopy-gen=package
+// +k8s:defaulter-gen=TypeMeta
+// +k8s:conversion-gen=github.com/gardener/gardener-extension-os-coreos/pkg/controller/config
+
+//go:generate crd-ref-docs --source-path=. --config=../../../../hack/api-reference/config.yaml --renderer=markdown --templates-dir=$GARDENER_HACK_DIR/api-reference/template --log-level=ERROR --output-path=../../../../hack/api-reference/config-v1beta1.md
+
+// Package v1beta1 contains the API for configuring the os-coreos extension. It is a promotion of v1alpha1 with the same
+// fields, defaults and semantics, so that the conversions are generated. The only difference is the encoding: an empty
+// ntp.daemon and empty servers lists are omitted, so that configs encoded from the Go types can be merged without
+// overwriting the daemon and servers they are merged into.
+// +groupName=config.coreos.os.extensions.gardener.cloud
+package v1beta1 // import "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
@@ -0,0 +1,350 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Daemon is the time synchronization service configured on the nodes
+// +kubebuilder:validation:Enum=systemd-timesyncd;ntpd;chrony
+type Daemon string
+
+const (
+	SystemdTimesyncd Daemon = "systemd-timesyncd"
+	NTPD             Daemon = "ntpd"
+	Chrony           Daemon = "chrony"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExtensionConfig is the configuration for the os-coreos extension.
+type ExtensionConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// EnableDocker specifies if docker should be available on the nodes.
+	// Defaults to false, as docker is only need for special use-cases.
+	// +kubebuilder:default=false
+	// +optional
+	EnableDocker *bool `json:"enableDocker,omitempty"`
+	// NTP to configure either systemd-timesyncd or ntpd
+	// +kubebuilder:default={}
+	// +optional
+	NTP *NTPConfig `json:"ntp,omitempty"`
+	// Sysctl to configure kernel parameters on the nodes
+	// +optional
+	Sysctl *SysctlConfig `json:"sysctl,omitempty"`
+	// Containerd to configure the containerd configuration file written during node provisioning
+	// +optional
+	Containerd *ContainerdConfig `json:"containerd,omitempty"`
+	// Policy Restrictions for the provider config of shoots, only allowed in the extension config
+	// +optional
+	Policy *PolicyConfig `json:"policy,omitempty"`
+	// TypeOverrides Settings for operating system configs of specific types, e.g. flatcar-lts, only allowed in the
+	// extension config. They are merged into the extension config in the given order before the provider config of the
+	// shoot.
+	// +optional
+	TypeOverrides []TypeOverride `json:"typeOverrides,omitempty"`
+	// ImageVersionRules Customizations enabled or disabled for machine image versions, only allowed in the extension
+	// config and type overrides. Later rules take precedence over earlier ones.
+	// +optional
+	ImageVersionRules []ImageVersionRule `json:"imageVersionRules,omitempty"`
+	// WorkerPoolOverrides Settings for the worker pools with the given names, e.g. to enable docker only on some pools
+	// sharing a machine image. They are merged in the given order after the provider config of the shoot.
+	// +optional
+	WorkerPoolOverrides []WorkerPoolOverride `json:"workerPoolOverrides,omitempty"`
+	// Profiles Named presets of settings, only allowed in the extension config
+	// +optional
+	Profiles []ConfigProfile `json:"profiles,omitempty"`
+	// Profile Name of the profile whose settings are merged before the provider config of the shoot. It is usually
+	// selected in the provider config, the one of the extension config is used if the shoot does not select a profile.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+}
+
+// Customization is an adjustment of the extension, which is only needed for some machine image versions
+// +kubebuilder:validation:Enum=logrotate-path;mask-sysupdate
+type Customization string
+
+const (
+	// CustomizationLogrotatePath points containerd-logrotate.service to /usr/bin/logrotate, where some Flatcar versions
+	// ship logrotate instead of /usr/sbin/logrotate. If no rule applies, the path is checked on the node.
+	CustomizationLogrotatePath Customization = "logrotate-path"
+	// CustomizationMaskSysupdate masks the timers of systemd-sysupdate. It is enabled unless a rule disables it.
+	CustomizationMaskSysupdate Customization = "mask-sysupdate"
+)
+
+// ImageVersionRule enables or disables customizations for the machine image versions matching a semver constraint
+type ImageVersionRule struct {
+	// Versions Semver constraint of the machine image versions, e.g. ">= 3975.2.0"
+	Versions string `json:"versions"`
+	// Enable Customizations applied to matching machine images
+	// +optional
+	Enable []Customization `json:"enable,omitempty"`
+	// Disable Customizations not applied to matching machine images
+	// +optional
+	Disable []Customization `json:"disable,omitempty"`
+}
+
+// TypeOverride contains settings for operating system configs of specific types
+type TypeOverride struct {
+	// Types of the operating system configs the settings apply to
+	Types []string `json:"types"`
+	// Config Settings merged into the extension config in the same way as the provider config of shoots. Policy and
+	// type overrides must not be set.
+	Config ExtensionConfig `json:"config"`
+}
+
+// WorkerPoolOverride contains settings for the worker pools with specific names
+type WorkerPoolOverride struct {
+	// Pools Names of the worker pools the settings apply to
+	Pools []string `json:"pools"`
+	// Config Settings merged into the config in the same way as the provider config of shoots. Only settings, which are
+	// allowed in the provider config, may be set.
+	Config ExtensionConfig `json:"config"`
+}
+
+// ConfigProfile is a named preset of settings shoots can select
+type ConfigProfile struct {
+	// Name of the profile, e.g. hardened
+	Name string `json:"name"`
+	// Config Settings merged into the config in the same way as the provider config of shoots. Only settings, which are
+	// allowed in the provider config, may be set.
+	Config ExtensionConfig `json:"config"`
+}
+
+// PolicyConfig restricts which fields shoots may set in the provider config of the machine image. Fields are addressed
+// by their path as in validation errors, e.g. ntp.ntpd.servers or sysctl.settings[vm.max_map_count].
+type PolicyConfig struct {
+	// LockedFields Paths of the fields shoots must neither set nor clear. Locking a field also locks its subfields.
+	// +optional
+	LockedFields []string `json:"lockedFields,omitempty"`
+	// AllowedValues Values shoots may set a field to
+	// +optional
+	AllowedValues []AllowedValues `json:"allowedValues,omitempty"`
+}
+
+// AllowedValues restricts the values shoots may set a field to
+type AllowedValues struct {
+	// Field Path of a field with a scalar value or a list of scalar values, e.g. ntp.daemon or sysctl.profiles
+	Field string `json:"field"`
+	// Values Allowed values of the field, each element of a list must be one of them
+	Values []string `json:"values"`
+}
+
+// NTPConfig General NTP Config for either systemd-timesyncd or ntpd
+type NTPConfig struct {
+	// Enabled Optionally disable or enable the extension to configure a timesync service for the machine
+	// +kubebuilder:default=true
+	Enabled *bool `json:"enabled,omitempty"`
+	// Daemon One of systemd-timesyncd, ntpd or chrony
+	// +kubebuilder:default="systemd-timesyncd"
+	// +optional
+	Daemon Daemon `json:"daemon,omitempty"`
+	// NTPD to configure the ntpd client
+	// +optional
+	NTPD *NTPDConfig `json:"ntpd,omitempty"`
+	// Timesyncd to configure the systemd-timesyncd client
+	// +optional
+	Timesyncd *TimesyncdConfig `json:"timesyncd,omitempty"`
+	// Chrony to configure the chrony client
+	// +optional
+	Chrony *ChronyConfig `json:"chrony,omitempty"`
+	// RequireAuthentication Only accept authenticated time, i.e. NTS for all chrony sources or key-based authentication
+	// for ntpd. systemd-timesyncd does not support authentication.
+	// +optional
+	RequireAuthentication *bool `json:"requireAuthentication,omitempty"`
+	// WaitForSync Delay the start of the kubelet until the clock is synchronized
+	// +optional
+	WaitForSync *NTPWaitForSync `json:"waitForSync,omitempty"`
+}
+
+// NTPWaitForSync configures whether the kubelet waits for the clock to be synchronized on boot
+type NTPWaitForSync struct {
+	// Enabled Order the kubelet after time-sync.target, which is reached once the clock is synchronized
+	Enabled bool `json:"enabled"`
+	// Timeout after which the kubelet is started even if the clock is not synchronized. Defaults to 2m.
+	// +kubebuilder:default="2m"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// NTPDConfig is the struct used in the ntp-config.conf.tpl template file
+type NTPDConfig struct {
+	// Servers List of ntp servers
+	// +optional
+	Servers []string `json:"servers,omitempty"`
+	// Sources List of ntp servers and pools with individual options, in addition to the servers
+	// +optional
+	Sources []NTPDSource `json:"sources,omitempty"`
+	// Interfaces for ntpd to bind to. Can be more than one.
+	Interfaces []string `json:"interfaces,omitempty"`
+	// Authentication Symmetric key used to authenticate the servers
+	// +optional
+	Authentication *NTPDAuthentication `json:"authentication,omitempty"`
+	// Restrict Access restrictions, each rendered as restrict line. Defaults to restricting all hosts but localhost.
+	// +optional
+	Restrict []string `json:"restrict,omitempty"`
+	// DriftFile Path of the drift file. Defaults to /var/lib/ntp/ntp.drift.
+	// +kubebuilder:default="/var/lib/ntp/ntp.drift"
+	// +optional
+	DriftFile *string `json:"driftFile,omitempty"`
+}
+
+// NTPDSource is a server or pool ntpd obtains the time from
+type NTPDSource struct {
+	// Address Host name or IP address of the server or pool
+	Address string `json:"address"`
+	// Pool Use multiple servers the address resolves to
+	// +optional
+	Pool bool `json:"pool,omitempty"`
+	// Prefer Prefer the source over the others
+	// +optional
+	Prefer bool `json:"prefer,omitempty"`
+	// MinPoll Minimum poll interval as power of two in seconds, between 3 and 17
+	// +optional
+	MinPoll *int32 `json:"minPoll,omitempty"`
+	// MaxPoll Maximum poll interval as power of two in seconds, between 3 and 17
+	// +optional
+	MaxPoll *int32 `json:"maxPoll,omitempty"`
+	// Key ID of the key to authenticate the source with, which must be the key of the authentication
+	// +optional
+	Key *int32 `json:"key,omitempty"`
+}
+
+// NTPDAuthentication is the symmetric key ntpd uses to authenticate the servers
+type NTPDAuthentication struct {
+	// KeyID ID of the key as configured on the servers, between 1 and 65535
+	KeyID int32 `json:"keyID"`
+	// Type of the key, one of MD5, SHA1 or AES128CMAC
+	Type string `json:"type"`
+	// SecretRef Reference to a Secret in the namespace of the OperatingSystemConfig containing the key in the data key key.
+	// The provider config of a shoot may only reference the resources of the shoot, whose names have the prefix ref-.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+// TimesyncdConfig is the struct used in the timesyncd.conf.tpl template file
+type TimesyncdConfig struct {
+	// Servers List of ntp servers
+	// +optional
+	Servers []string `json:"servers,omitempty"`
+	// FallbackServers List of ntp servers used if none of the servers can be reached. Defaults to the servers of the image.
+	// +optional
+	FallbackServers []string `json:"fallbackServers,omitempty"`
+	// PollIntervalMin Minimum poll interval, at least 16s. Defaults to 32s.
+	// +kubebuilder:default="32s"
+	// +optional
+	PollIntervalMin *metav1.Duration `json:"pollIntervalMin,omitempty"`
+	// PollIntervalMax Maximum poll interval, at least the minimum poll interval. Defaults to 2048s.
+	// +kubebuilder:default="2048s"
+	// +optional
+	PollIntervalMax *metav1.Duration `json:"pollIntervalMax,omitempty"`
+}
+
+// ChronyConfig is the struct used in the chrony.conf.tpl template file
+type ChronyConfig struct {
+	// Servers List of ntp servers
+	// +optional
+	Servers []ChronySource `json:"servers,omitempty"`
+	// Pools List of ntp pools, chrony uses multiple servers of each pool
+	// +optional
+	Pools []ChronySource `json:"pools,omitempty"`
+	// RefClocks List of PTP hardware clocks, e.g. the one of the hypervisor
+	// +optional
+	RefClocks []ChronyRefClock `json:"refClocks,omitempty"`
+	// MakeStep Step the clock instead of slewing it during the first updates. Defaults to a threshold of 1s in the first 3 updates.
+	// +optional
+	MakeStep *ChronyMakeStep `json:"makeStep,omitempty"`
+	// RTCSync Periodically copy the system time to the real-time clock. Defaults to true.
+	// +kubebuilder:default=true
+	// +optional
+	RTCSync *bool `json:"rtcSync,omitempty"`
+	// Allow Networks in CIDR notation, which may use the node as ntp server
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+	// NTSTrustedCertificates PEM encoded CA certificates to verify the NTS-KE servers, in addition to the system ones
+	// +optional
+	NTSTrustedCertificates *string `json:"ntsTrustedCertificates,omitempty"`
+}
+
+// ChronySource is a server or pool chrony obtains the time from
+type ChronySource struct {
+	// Address Host name or IP address of the server or pool
+	Address string `json:"address"`
+	// NTS Authenticate the source with Network Time Security
+	// +optional
+	NTS bool `json:"nts,omitempty"`
+}
+
+// ChronyRefClock is a PTP hardware clock chrony obtains the time from
+type ChronyRefClock struct {
+	// Device Path of the PTP hardware clock device, e.g. /dev/ptp_hyperv
+	Device string `json:"device"`
+	// Poll Interval of the clock updates, as power of two in seconds
+	// +optional
+	Poll *int32 `json:"poll,omitempty"`
+	// DPoll Interval of the samples of the device, as power of two in seconds
+	// +optional
+	DPoll *int32 `json:"dpoll,omitempty"`
+}
+
+// ChronyMakeStep configures when chrony steps the clock
+type ChronyMakeStep struct {
+	// Threshold Offset above which the clock is stepped
+	Threshold metav1.Duration `json:"threshold"`
+	// Limit Number of updates in which the clock may be stepped, -1 for no limit
+	Limit int32 `json:"limit"`
+}
+
+// ContainerdConfig contains the settings rendered into /etc/containerd/config.toml
+type ContainerdConfig struct {
+	// ConfigVersion Version of the containerd configuration file, either 2 or 3. Version 3 requires containerd 2.x.
+	// Defaults to 2, which is understood by containerd 1.7 and 2.x.
+	// +kubebuilder:default=2
+	// +optional
+	ConfigVersion *int32 `json:"configVersion,omitempty"`
+	// SandboxImage Image used for the pod sandbox until gardener-node-agent configures the one of the shoot
+	// +optional
+	SandboxImage *string `json:"sandboxImage,omitempty"`
+	// Snapshotter Snapshotter used by the CRI plugin. Defaults to overlayfs.
+	// +kubebuilder:default="overlayfs"
+	// +optional
+	Snapshotter *string `json:"snapshotter,omitempty"`
+	// RegistryAuth Credentials containerd uses to pull images from private registries. The credentials of a shoot are
+	// merged with the ones of the extension config by registry. They require config version 3, i.e. containerd 2.x.
+	// +optional
+	// +patchMergeKey=registry
+	// +patchStrategy=merge
+	RegistryAuth []RegistryAuth `json:"registryAuth,omitempty" patchStrategy:"merge" patchMergeKey:"registry"`
+}
+
+// RegistryAuth references the credentials of a registry
+type RegistryAuth struct {
+	// Registry Host of the registry, optionally including the port, e.g. registry.example.com:5000
+	Registry string `json:"registry"`
+	// SecretRef Reference to a Secret in the namespace of the OperatingSystemConfig. The Secret either is of type
+	// kubernetes.io/dockerconfigjson and contains an entry for the registry, or it contains the keys username and
+	// password, auth or identitytoken. The provider config of a shoot may only reference the resources of the shoot,
+	// whose names have the prefix ref-.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+// SysctlProfile is the name of a predefined set of kernel parameters.
+// +kubebuilder:validation:Enum=network-heavy;hardened
+type SysctlProfile string
+
+const (
+	// SysctlProfileNetworkHeavy tunes the network stack for nodes with many connections and high throughput.
+	SysctlProfileNetworkHeavy SysctlProfile = "network-heavy"
+	// SysctlProfileHardened restricts kernel features which are commonly used in exploits.
+	SysctlProfileHardened SysctlProfile = "hardened"
+)
+
+// SysctlConfig contains the kernel parameters written to /etc/sysctl.d
+type SysctlConfig struct {
+	// Profiles List of predefined sets of kernel parameters, applied in the given order
+	// +optional
+	Profiles []SysctlProfile `json:"profiles,omitempty"`
+	// Settings Kernel parameters applied on top of the profiles. Keys must start with net., vm., kernel. or fs.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+}
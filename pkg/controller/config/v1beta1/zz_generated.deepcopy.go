@@ -0,0 +1,621 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedValues) DeepCopyInto(out *AllowedValues) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedValues.
+func (in *AllowedValues) DeepCopy() *AllowedValues {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedValues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChronyConfig) DeepCopyInto(out *ChronyConfig) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]ChronySource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]ChronySource, len(*in))
+		copy(*out, *in)
+	}
+	if in.RefClocks != nil {
+		in, out := &in.RefClocks, &out.RefClocks
+		*out = make([]ChronyRefClock, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MakeStep != nil {
+		in, out := &in.MakeStep, &out.MakeStep
+		*out = new(ChronyMakeStep)
+		**out = **in
+	}
+	if in.RTCSync != nil {
+		in, out := &in.RTCSync, &out.RTCSync
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NTSTrustedCertificates != nil {
+		in, out := &in.NTSTrustedCertificates, &out.NTSTrustedCertificates
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChronyConfig.
+func (in *ChronyConfig) DeepCopy() *ChronyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ChronyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChronyMakeStep) DeepCopyInto(out *ChronyMakeStep) {
+	*out = *in
+	out.Threshold = in.Threshold
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChronyMakeStep.
+func (in *ChronyMakeStep) DeepCopy() *ChronyMakeStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ChronyMakeStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChronyRefClock) DeepCopyInto(out *ChronyRefClock) {
+	*out = *in
+	if in.Poll != nil {
+		in, out := &in.Poll, &out.Poll
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DPoll != nil {
+		in, out := &in.DPoll, &out.DPoll
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChronyRefClock.
+func (in *ChronyRefClock) DeepCopy() *ChronyRefClock {
+	if in == nil {
+		return nil
+	}
+	out := new(ChronyRefClock)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChronySource) DeepCopyInto(out *ChronySource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChronySource.
+func (in *ChronySource) DeepCopy() *ChronySource {
+	if in == nil {
+		return nil
+	}
+	out := new(ChronySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigProfile) DeepCopyInto(out *ConfigProfile) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigProfile.
+func (in *ConfigProfile) DeepCopy() *ConfigProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerdConfig) DeepCopyInto(out *ContainerdConfig) {
+	*out = *in
+	if in.ConfigVersion != nil {
+		in, out := &in.ConfigVersion, &out.ConfigVersion
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SandboxImage != nil {
+		in, out := &in.SandboxImage, &out.SandboxImage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Snapshotter != nil {
+		in, out := &in.Snapshotter, &out.Snapshotter
+		*out = new(string)
+		**out = **in
+	}
+	if in.RegistryAuth != nil {
+		in, out := &in.RegistryAuth, &out.RegistryAuth
+		*out = make([]RegistryAuth, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdConfig.
+func (in *ContainerdConfig) DeepCopy() *ContainerdConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerdConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionConfig) DeepCopyInto(out *ExtensionConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.EnableDocker != nil {
+		in, out := &in.EnableDocker, &out.EnableDocker
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NTP != nil {
+		in, out := &in.NTP, &out.NTP
+		*out = new(NTPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sysctl != nil {
+		in, out := &in.Sysctl, &out.Sysctl
+		*out = new(SysctlConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(ContainerdConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(PolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TypeOverrides != nil {
+		in, out := &in.TypeOverrides, &out.TypeOverrides
+		*out = make([]TypeOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImageVersionRules != nil {
+		in, out := &in.ImageVersionRules, &out.ImageVersionRules
+		*out = make([]ImageVersionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.WorkerPoolOverrides != nil {
+		in, out := &in.WorkerPoolOverrides, &out.WorkerPoolOverrides
+		*out = make([]WorkerPoolOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]ConfigProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionConfig.
+func (in *ExtensionConfig) DeepCopy() *ExtensionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExtensionConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageVersionRule) DeepCopyInto(out *ImageVersionRule) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = make([]Customization, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disable != nil {
+		in, out := &in.Disable, &out.Disable
+		*out = make([]Customization, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageVersionRule.
+func (in *ImageVersionRule) DeepCopy() *ImageVersionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageVersionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NTPConfig) DeepCopyInto(out *NTPConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NTPD != nil {
+		in, out := &in.NTPD, &out.NTPD
+		*out = new(NTPDConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timesyncd != nil {
+		in, out := &in.Timesyncd, &out.Timesyncd
+		*out = new(TimesyncdConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Chrony != nil {
+		in, out := &in.Chrony, &out.Chrony
+		*out = new(ChronyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequireAuthentication != nil {
+		in, out := &in.RequireAuthentication, &out.RequireAuthentication
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WaitForSync != nil {
+		in, out := &in.WaitForSync, &out.WaitForSync
+		*out = new(NTPWaitForSync)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NTPConfig.
+func (in *NTPConfig) DeepCopy() *NTPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NTPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NTPDAuthentication) DeepCopyInto(out *NTPDAuthentication) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NTPDAuthentication.
+func (in *NTPDAuthentication) DeepCopy() *NTPDAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(NTPDAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NTPDConfig) DeepCopyInto(out *NTPDConfig) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]NTPDSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(NTPDAuthentication)
+		**out = **in
+	}
+	if in.Restrict != nil {
+		in, out := &in.Restrict, &out.Restrict
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftFile != nil {
+		in, out := &in.DriftFile, &out.DriftFile
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NTPDConfig.
+func (in *NTPDConfig) DeepCopy() *NTPDConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NTPDConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NTPDSource) DeepCopyInto(out *NTPDSource) {
+	*out = *in
+	if in.MinPoll != nil {
+		in, out := &in.MinPoll, &out.MinPoll
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPoll != nil {
+		in, out := &in.MaxPoll, &out.MaxPoll
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NTPDSource.
+func (in *NTPDSource) DeepCopy() *NTPDSource {
+	if in == nil {
+		return nil
+	}
+	out := new(NTPDSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NTPWaitForSync) DeepCopyInto(out *NTPWaitForSync) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NTPWaitForSync.
+func (in *NTPWaitForSync) DeepCopy() *NTPWaitForSync {
+	if in == nil {
+		return nil
+	}
+	out := new(NTPWaitForSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyConfig) DeepCopyInto(out *PolicyConfig) {
+	*out = *in
+	if in.LockedFields != nil {
+		in, out := &in.LockedFields, &out.LockedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedValues != nil {
+		in, out := &in.AllowedValues, &out.AllowedValues
+		*out = make([]AllowedValues, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyConfig.
+func (in *PolicyConfig) DeepCopy() *PolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryAuth) DeepCopyInto(out *RegistryAuth) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryAuth.
+func (in *RegistryAuth) DeepCopy() *RegistryAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SysctlConfig) DeepCopyInto(out *SysctlConfig) {
+	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]SysctlProfile, len(*in))
+		copy(*out, *in)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SysctlConfig.
+func (in *SysctlConfig) DeepCopy() *SysctlConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SysctlConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimesyncdConfig) DeepCopyInto(out *TimesyncdConfig) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FallbackServers != nil {
+		in, out := &in.FallbackServers, &out.FallbackServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PollIntervalMin != nil {
+		in, out := &in.PollIntervalMin, &out.PollIntervalMin
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PollIntervalMax != nil {
+		in, out := &in.PollIntervalMax, &out.PollIntervalMax
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimesyncdConfig.
+func (in *TimesyncdConfig) DeepCopy() *TimesyncdConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TimesyncdConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypeOverride) DeepCopyInto(out *TypeOverride) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Config.DeepCopyInto(&out.Config)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypeOverride.
+func (in *TypeOverride) DeepCopy() *TypeOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(TypeOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerPoolOverride) DeepCopyInto(out *WorkerPoolOverride) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Config.DeepCopyInto(&out.Config)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerPoolOverride.
+func (in *WorkerPoolOverride) DeepCopy() *WorkerPoolOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerPoolOverride)
+	in.DeepCopyInto(out)
+	return out
+}
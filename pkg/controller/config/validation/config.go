@@ -0,0 +1,751 @@
+package validation
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Masterminds/semver/v3"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+func ValidateExtensionConfig(config *coreosconfig.ExtensionConfig) field.ErrorList {
+	allErrs := validateSettings(config, nil)
+
+	if config.Policy != nil {
+		allErrs = append(allErrs, validatePolicyConfig(config.Policy, field.NewPath("policy"))...)
+	}
+
+	for i, override := range config.TypeOverrides {
+		allErrs = append(allErrs, validateTypeOverride(override, field.NewPath("typeOverrides").Index(i))...)
+	}
+
+	allErrs = append(allErrs, validateImageVersionRules(config.ImageVersionRules, field.NewPath("imageVersionRules"))...)
+
+	for i, override := range config.WorkerPoolOverrides {
+		allErrs = append(allErrs, validateWorkerPoolOverride(override, field.NewPath("workerPoolOverrides").Index(i))...)
+	}
+
+	allErrs = append(allErrs, validateProfiles(config.Profiles, field.NewPath("profiles"))...)
+	// The provider config of shoots selects one of the profiles of the extension config, which is checked when merging.
+	if len(config.Profiles) > 0 {
+		allErrs = append(allErrs, ValidateProfileSelection(config.Profile, config.Profiles, field.NewPath("profile"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateProviderConfigSecretRefs makes sure that the given provider config of a shoot only references secrets, which
+// the shoot references as resources. Gardener copies them into the namespace of the OperatingSystemConfig with the
+// prefix ref-. Other secrets of the namespace, e.g. the credentials of the cloud provider, must not end up on the nodes.
+func ValidateProviderConfigSecretRefs(config *coreosconfig.ExtensionConfig) field.ErrorList {
+	allErrs := validateSettingsSecretRefs(config, nil)
+
+	for i, override := range config.WorkerPoolOverrides {
+		allErrs = append(allErrs, validateSettingsSecretRefs(&override.Config, field.NewPath("workerPoolOverrides").Index(i).Child("config"))...)
+	}
+
+	return allErrs
+}
+
+func validateSettingsSecretRefs(config *coreosconfig.ExtensionConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config.NTP != nil && config.NTP.NTPD != nil && config.NTP.NTPD.Authentication != nil {
+		allErrs = append(allErrs, validateReferencedSecretName(config.NTP.NTPD.Authentication.SecretRef.Name, fldPath.Child("ntp", "ntpd", "authentication", "secretRef", "name"))...)
+	}
+
+	if config.Containerd != nil {
+		for i, registryAuth := range config.Containerd.RegistryAuth {
+			allErrs = append(allErrs, validateReferencedSecretName(registryAuth.SecretRef.Name, fldPath.Child("containerd", "registryAuth").Index(i).Child("secretRef", "name"))...)
+		}
+	}
+
+	return allErrs
+}
+
+func validateReferencedSecretName(name string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if name != "" && !strings.HasPrefix(name, v1beta1constants.ReferencedResourcesPrefix) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf("must start with %q, i.e. reference a secret referenced in the resources of the shoot", v1beta1constants.ReferencedResourcesPrefix)))
+	}
+	return allErrs
+}
+
+// validateCertificates checks that the given data only consists of PEM encoded certificates.
+func validateCertificates(data string) error {
+	rest := []byte(strings.TrimSpace(data))
+	if len(rest) == 0 {
+		return fmt.Errorf("must contain at least one PEM encoded certificate")
+	}
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil || block.Type != "CERTIFICATE" {
+			return fmt.Errorf("must only contain PEM encoded certificates")
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		rest = bytes.TrimSpace(rest)
+	}
+	return nil
+}
+
+// validateSettings validates the settings of the given config, which are allowed in the provider config of shoots and
+// in overrides.
+func validateSettings(config *coreosconfig.ExtensionConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	ntpPath := fldPath.Child("ntp")
+
+	if config.NTP != nil {
+		// The daemon is empty in configs which are not defaulted, i.e. in the provider config of a shoot. The checks
+		// depending on the daemon are done for the merged config then.
+		if config.NTP.Daemon != "" {
+			allErrs = append(allErrs, validateNTPDaemon(config.NTP, ntpPath)...)
+		}
+
+		if config.NTP.NTPD != nil {
+			allErrs = append(allErrs, validateNTPDConfig(config.NTP.NTPD, ntpPath.Child("ntpd"))...)
+		}
+
+		if config.NTP.Timesyncd != nil {
+			allErrs = append(allErrs, validateTimesyncdConfig(config.NTP.Timesyncd, ntpPath.Child("timesyncd"))...)
+		}
+
+		if config.NTP.Chrony != nil {
+			allErrs = append(allErrs, validateChronyConfig(config.NTP.Chrony, ntpPath.Child("chrony"))...)
+		}
+
+		if config.NTP.WaitForSync != nil {
+			allErrs = append(allErrs, validateNTPWaitForSync(config.NTP.WaitForSync, ntpPath.Child("waitForSync"))...)
+		}
+	}
+
+	if config.Sysctl != nil {
+		allErrs = append(allErrs, validateSysctlConfig(config.Sysctl, fldPath.Child("sysctl"))...)
+	}
+
+	if config.Containerd != nil {
+		allErrs = append(allErrs, validateContainerdConfig(config.Containerd, fldPath.Child("containerd"))...)
+	}
+
+	return allErrs
+}
+
+// validateTypeOverride makes sure that the override names the types it applies to and only contains settings.
+func validateTypeOverride(override coreosconfig.TypeOverride, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(override.Types) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("types"), "at least one type is required"))
+	}
+	types := sets.New[string]()
+	for i, t := range override.Types {
+		idxPath := fldPath.Child("types").Index(i)
+		if t == "" {
+			allErrs = append(allErrs, field.Required(idxPath, "type must not be empty"))
+		} else if types.Has(t) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, t))
+		}
+		types.Insert(t)
+	}
+
+	configPath := fldPath.Child("config")
+	if override.Config.Policy != nil {
+		allErrs = append(allErrs, field.Forbidden(configPath.Child("policy"), "may only be set at the top level"))
+	}
+	if override.Config.TypeOverrides != nil {
+		allErrs = append(allErrs, field.Forbidden(configPath.Child("typeOverrides"), "may only be set at the top level"))
+	}
+	if override.Config.Profiles != nil {
+		allErrs = append(allErrs, field.Forbidden(configPath.Child("profiles"), "may only be set at the top level"))
+	}
+	allErrs = append(allErrs, validateSettings(&override.Config, configPath)...)
+	allErrs = append(allErrs, validateImageVersionRules(override.Config.ImageVersionRules, configPath.Child("imageVersionRules"))...)
+	for i, poolOverride := range override.Config.WorkerPoolOverrides {
+		allErrs = append(allErrs, validateWorkerPoolOverride(poolOverride, configPath.Child("workerPoolOverrides").Index(i))...)
+	}
+
+	return allErrs
+}
+
+// validateWorkerPoolOverride makes sure that the override names the worker pools it applies to and only contains
+// settings.
+func validateWorkerPoolOverride(override coreosconfig.WorkerPoolOverride, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if len(override.Pools) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("pools"), "at least one worker pool is required"))
+	}
+	pools := sets.New[string]()
+	for i, pool := range override.Pools {
+		idxPath := fldPath.Child("pools").Index(i)
+		if pool == "" {
+			allErrs = append(allErrs, field.Required(idxPath, "worker pool must not be empty"))
+		} else if pools.Has(pool) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, pool))
+		}
+		pools.Insert(pool)
+	}
+
+	allErrs = append(allErrs, validateOnlySettings(&override.Config, fldPath.Child("config"))...)
+
+	return allErrs
+}
+
+// validateProfiles makes sure that the profiles have unique names and only contain settings.
+func validateProfiles(profiles []coreosconfig.ConfigProfile, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.New[string]()
+	for i, profile := range profiles {
+		idxPath := fldPath.Index(i)
+		namePath := idxPath.Child("name")
+		if profile.Name == "" {
+			allErrs = append(allErrs, field.Required(namePath, "name must not be empty"))
+		} else if errs := validation.IsDNS1123Label(profile.Name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(namePath, profile.Name, strings.Join(errs, ", ")))
+		} else if names.Has(profile.Name) {
+			allErrs = append(allErrs, field.Duplicate(namePath, profile.Name))
+		}
+		names.Insert(profile.Name)
+
+		allErrs = append(allErrs, validateOnlySettings(&profile.Config, idxPath.Child("config"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateProfileSelection makes sure that the given name of the selected profile, if any, refers to one of the given
+// profiles.
+func ValidateProfileSelection(name string, profiles []coreosconfig.ConfigProfile, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if name == "" {
+		return allErrs
+	}
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return allErrs
+		}
+		names = append(names, profile.Name)
+	}
+
+	return append(allErrs, field.NotSupported(fldPath, name, names))
+}
+
+// validateOnlySettings makes sure that the given config of an override or profile only contains settings, which are
+// also allowed in the provider config of shoots.
+func validateOnlySettings(config *coreosconfig.ExtensionConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"policy", config.Policy != nil},
+		{"typeOverrides", config.TypeOverrides != nil},
+		{"imageVersionRules", config.ImageVersionRules != nil},
+		{"workerPoolOverrides", config.WorkerPoolOverrides != nil},
+		{"profiles", config.Profiles != nil},
+		{"profile", config.Profile != ""},
+	} {
+		if f.set {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(f.name), "may only be set at the top level"))
+		}
+	}
+	allErrs = append(allErrs, validateSettings(config, fldPath)...)
+
+	return allErrs
+}
+
+// validCustomizations are the customizations which can be enabled or disabled by image version rules.
+var validCustomizations = sets.New(coreosconfig.CustomizationLogrotatePath, coreosconfig.CustomizationMaskSysupdate)
+
+// validateImageVersionRules makes sure that the rules have valid semver constraints and only name known customizations.
+func validateImageVersionRules(rules []coreosconfig.ImageVersionRule, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, rule := range rules {
+		idxPath := fldPath.Index(i)
+		if rule.Versions == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("versions"), "a semver constraint is required"))
+		} else if _, err := semver.NewConstraint(rule.Versions); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("versions"), rule.Versions, err.Error()))
+		}
+		if len(rule.Enable) == 0 && len(rule.Disable) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath, "at least one customization must be enabled or disabled"))
+		}
+
+		enabled := sets.New[coreosconfig.Customization]()
+		for j, customization := range rule.Enable {
+			if !validCustomizations.Has(customization) {
+				allErrs = append(allErrs, field.NotSupported(idxPath.Child("enable").Index(j), customization, sets.List(validCustomizations)))
+			}
+			enabled.Insert(customization)
+		}
+		for j, customization := range rule.Disable {
+			if !validCustomizations.Has(customization) {
+				allErrs = append(allErrs, field.NotSupported(idxPath.Child("disable").Index(j), customization, sets.List(validCustomizations)))
+			} else if enabled.Has(customization) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("disable").Index(j), customization, "customization must not be enabled and disabled by the same rule"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validDaemonNames are the supported NTP daemons.
+var validDaemonNames = sets.New(coreosconfig.SystemdTimesyncd, coreosconfig.NTPD, coreosconfig.Chrony)
+
+// validateNTPDaemon makes sure that the daemon is supported and that only its section is configured.
+func validateNTPDaemon(config *coreosconfig.NTPConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// Make sure daemon name is valid
+	if !validDaemonNames.Has(config.Daemon) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("daemon"), config.Daemon, sets.List(validDaemonNames)))
+	}
+
+	// Check if user configured another daemon with ntpd config
+	if config.Daemon != coreosconfig.NTPD && config.NTPD != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ntpd"), "ntpd config is only allowed with daemon ntpd"))
+	}
+
+	// Check if user configured another daemon with systemd-timesyncd config
+	if config.Daemon != coreosconfig.SystemdTimesyncd && config.Timesyncd != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("timesyncd"), "systemd-timesyncd config is only allowed with daemon systemd-timesyncd"))
+	}
+
+	// Check if user configured another daemon with chrony config
+	if config.Daemon != coreosconfig.Chrony && config.Chrony != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("chrony"), "chrony config is only allowed with daemon chrony"))
+	}
+
+	if ptr.Deref(config.RequireAuthentication, false) {
+		allErrs = append(allErrs, validateNTPAuthenticationRequired(config, fldPath)...)
+	}
+
+	return allErrs
+}
+
+// ValidateNTPDaemonConfigured makes sure that the section of ntpd is configured if it is the selected daemon.
+// systemd-timesyncd and chrony fall back to the config shipped with the image, ntpd has none. It is meant for the merged
+// config including the time sources of the cloud provider, which the extension config and the provider config may rely
+// on.
+func ValidateNTPDaemonConfigured(config *coreosconfig.NTPConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config == nil || !ptr.Deref(config.Enabled, true) || config.Daemon != coreosconfig.NTPD {
+		return allErrs
+	}
+
+	if config.NTPD == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("ntpd"), "ntpd config is required with daemon ntpd, since the cloud provider does not offer default time sources"))
+	}
+
+	return allErrs
+}
+
+func validateNTPDConfig(config *coreosconfig.NTPDConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(config.Servers) == 0 && len(config.Sources) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("servers"), "a list of NTP servers is required"))
+	}
+	addresses := sets.New[string]()
+	for i, server := range config.Servers {
+		allErrs = append(allErrs, validateNTPServerAddress(server, addresses, fldPath.Child("servers").Index(i))...)
+	}
+	for i, source := range config.Sources {
+		allErrs = append(allErrs, validateNTPDSource(source, config.Authentication, fldPath.Child("sources").Index(i))...)
+		allErrs = append(allErrs, validateNTPServerAddress(source.Address, addresses, fldPath.Child("sources").Index(i).Child("address"))...)
+	}
+	interfaces := sets.New[string]()
+	for i, iface := range config.Interfaces {
+		idxPath := fldPath.Child("interfaces").Index(i)
+		if net.ParseIP(iface) == nil && !isValidInterfaceName(iface) {
+			allErrs = append(allErrs, field.Invalid(idxPath, iface, "must be an IP address or a network interface name of at most 15 characters without '/', ':' and whitespace"))
+		} else if interfaces.Has(iface) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, iface))
+		}
+		interfaces.Insert(iface)
+	}
+	if config.Authentication != nil {
+		allErrs = append(allErrs, validateNTPDAuthentication(config.Authentication, fldPath.Child("authentication"))...)
+	}
+	for i, restrict := range config.Restrict {
+		if strings.TrimSpace(restrict) == "" || strings.ContainsAny(restrict, "\r\n") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("restrict").Index(i), restrict, "must be a single restrict line without the restrict keyword"))
+		}
+	}
+	if config.DriftFile != nil && (!filepath.IsAbs(*config.DriftFile) || strings.ContainsAny(*config.DriftFile, " \t\r\n")) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("driftFile"), *config.DriftFile, "must be an absolute path without whitespace"))
+	}
+	return allErrs
+}
+
+const (
+	// minNTPDPoll and maxNTPDPoll are the bounds of the poll intervals of ntpd, as power of two in seconds.
+	minNTPDPoll = 3
+	maxNTPDPoll = 17
+)
+
+func validateNTPDSource(source coreosconfig.NTPDSource, authentication *coreosconfig.NTPDAuthentication, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if source.MinPoll != nil && (*source.MinPoll < minNTPDPoll || *source.MinPoll > maxNTPDPoll) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minPoll"), *source.MinPoll, fmt.Sprintf("must be between %d and %d", minNTPDPoll, maxNTPDPoll)))
+	}
+	if source.MaxPoll != nil && (*source.MaxPoll < minNTPDPoll || *source.MaxPoll > maxNTPDPoll) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPoll"), *source.MaxPoll, fmt.Sprintf("must be between %d and %d", minNTPDPoll, maxNTPDPoll)))
+	}
+	if source.MinPoll != nil && source.MaxPoll != nil && *source.MaxPoll < *source.MinPoll {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPoll"), *source.MaxPoll, "must not be smaller than minPoll"))
+	}
+	if source.Key != nil && (authentication == nil || authentication.KeyID != *source.Key) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), *source.Key, "must be the key ID of the authentication"))
+	}
+	return allErrs
+}
+
+// validNTPDKeyTypes are the key types supported by the ntpd of Flatcar.
+var validNTPDKeyTypes = sets.New("MD5", "SHA1", "AES128CMAC")
+
+func validateNTPDAuthentication(config *coreosconfig.NTPDAuthentication, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config.KeyID < 1 || config.KeyID > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyID"), config.KeyID, "must be between 1 and 65535"))
+	}
+	if !validNTPDKeyTypes.Has(config.Type) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), config.Type, sets.List(validNTPDKeyTypes)))
+	}
+	if config.SecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("secretRef", "name"), "must reference a secret"))
+	}
+	return allErrs
+}
+
+// validateNTPAuthenticationRequired makes sure that the configured daemon only accepts authenticated time.
+func validateNTPAuthenticationRequired(config *coreosconfig.NTPConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch config.Daemon {
+	case coreosconfig.NTPD:
+		if config.NTPD == nil || config.NTPD.Authentication == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("ntpd", "authentication"), "authentication is required"))
+			break
+		}
+		for i, source := range config.NTPD.Sources {
+			if source.Key == nil {
+				allErrs = append(allErrs, field.Required(fldPath.Child("ntpd", "sources").Index(i).Child("key"), "must be set if authentication is required"))
+			}
+		}
+	case coreosconfig.Chrony:
+		if config.Chrony == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("chrony"), "sources with NTS are required"))
+			break
+		}
+		for i, source := range config.Chrony.Servers {
+			if !source.NTS {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("chrony", "servers").Index(i).Child("nts"), source.NTS, "must be enabled if authentication is required"))
+			}
+		}
+		for i, source := range config.Chrony.Pools {
+			if !source.NTS {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("chrony", "pools").Index(i).Child("nts"), source.NTS, "must be enabled if authentication is required"))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("requireAuthentication"), fmt.Sprintf("%s does not support authentication", config.Daemon)))
+	}
+	return allErrs
+}
+
+// validateNTPServerAddress makes sure that the given address is a host name or an IP address, which has not been
+// configured before. The address is added to the given set of configured addresses.
+func validateNTPServerAddress(address string, addresses sets.Set[string], fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// Host names are case-insensitive, and the daemons also accept them with a trailing dot.
+	normalized := strings.TrimSuffix(strings.ToLower(address), ".")
+	if net.ParseIP(address) == nil && len(validation.IsDNS1123Subdomain(normalized)) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, address, "must be a host name or an IP address"))
+	} else if addresses.Has(normalized) {
+		allErrs = append(allErrs, field.Duplicate(fldPath, address))
+	}
+	addresses.Insert(normalized)
+	return allErrs
+}
+
+// maxInterfaceNameLength is the maximum length of network interface names on Linux, i.e. IFNAMSIZ without the
+// terminating null byte.
+const maxInterfaceNameLength = 15
+
+// isValidInterfaceName implements the naming rules of network interfaces of the Linux kernel.
+func isValidInterfaceName(name string) bool {
+	if name == "" || len(name) > maxInterfaceNameLength || name == "." || name == ".." {
+		return false
+	}
+	return !strings.ContainsFunc(name, func(r rune) bool {
+		return r == '/' || r == ':' || unicode.IsSpace(r)
+	})
+}
+
+// minTimesyncdPollInterval is the lower bound of the poll intervals accepted by systemd-timesyncd.
+const minTimesyncdPollInterval = 16 * time.Second
+
+func validateTimesyncdConfig(config *coreosconfig.TimesyncdConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(config.Servers) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("servers"), "a list of NTP servers is required"))
+	}
+
+	servers := sets.New[string]()
+	for i, server := range config.Servers {
+		allErrs = append(allErrs, validateNTPServerAddress(server, servers, fldPath.Child("servers").Index(i))...)
+	}
+	fallbackServers := sets.New[string]()
+	for i, server := range config.FallbackServers {
+		allErrs = append(allErrs, validateNTPServerAddress(server, fallbackServers, fldPath.Child("fallbackServers").Index(i))...)
+	}
+
+	allErrs = append(allErrs, validateTimesyncdPollInterval(config.PollIntervalMin, fldPath.Child("pollIntervalMin"))...)
+	allErrs = append(allErrs, validateTimesyncdPollInterval(config.PollIntervalMax, fldPath.Child("pollIntervalMax"))...)
+	if config.PollIntervalMin != nil && config.PollIntervalMax != nil && config.PollIntervalMax.Duration < config.PollIntervalMin.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pollIntervalMax"), config.PollIntervalMax.Duration.String(), "must not be smaller than pollIntervalMin"))
+	}
+
+	return allErrs
+}
+
+func validateTimesyncdPollInterval(interval *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if interval == nil {
+		return allErrs
+	}
+	if interval.Duration < minTimesyncdPollInterval {
+		allErrs = append(allErrs, field.Invalid(fldPath, interval.Duration.String(), fmt.Sprintf("must be at least %s", minTimesyncdPollInterval)))
+	} else if interval.Duration%time.Second != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, interval.Duration.String(), "must be a whole number of seconds"))
+	}
+	return allErrs
+}
+
+// maxWaitForSyncTimeout is the maximum time the kubelet may wait for the clock to be synchronized, so that nodes with
+// unreachable time sources still join the cluster in time.
+const maxWaitForSyncTimeout = 30 * time.Minute
+
+func validateNTPWaitForSync(config *coreosconfig.NTPWaitForSync, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config.Timeout == nil {
+		return allErrs
+	}
+	timeout := config.Timeout.Duration
+	if timeout < time.Second || timeout > maxWaitForSyncTimeout {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), timeout.String(), fmt.Sprintf("must be between 1s and %s", maxWaitForSyncTimeout)))
+	} else if timeout%time.Second != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), timeout.String(), "must be a whole number of seconds"))
+	}
+	return allErrs
+}
+
+func validateChronyConfig(config *coreosconfig.ChronyConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(config.Servers) == 0 && len(config.Pools) == 0 && len(config.RefClocks) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("servers"), "a list of NTP servers, pools or reference clocks is required"))
+	}
+
+	addresses := sets.New[string]()
+	for i, source := range config.Servers {
+		allErrs = append(allErrs, validateNTPServerAddress(source.Address, addresses, fldPath.Child("servers").Index(i).Child("address"))...)
+	}
+	for i, source := range config.Pools {
+		allErrs = append(allErrs, validateNTPServerAddress(source.Address, addresses, fldPath.Child("pools").Index(i).Child("address"))...)
+	}
+	for i, refClock := range config.RefClocks {
+		allErrs = append(allErrs, validateChronyRefClock(refClock, fldPath.Child("refClocks").Index(i))...)
+	}
+
+	if config.MakeStep != nil {
+		if config.MakeStep.Threshold.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("makeStep", "threshold"), config.MakeStep.Threshold.Duration.String(), "must be positive"))
+		}
+		if config.MakeStep.Limit < -1 || config.MakeStep.Limit == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("makeStep", "limit"), config.MakeStep.Limit, "must be positive or -1 for no limit"))
+		}
+	}
+
+	for i, allow := range config.Allow {
+		if _, _, err := net.ParseCIDR(allow); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allow").Index(i), allow, "must be a network in CIDR notation"))
+		}
+	}
+
+	if config.NTSTrustedCertificates != nil {
+		if err := validateCertificates(*config.NTSTrustedCertificates); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ntsTrustedCertificates"), "", err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+const (
+	// minChronyRefClockPoll and maxChronyRefClockPoll are the bounds of the poll intervals of chrony reference clocks,
+	// as power of two in seconds.
+	minChronyRefClockPoll = -6
+	maxChronyRefClockPoll = 24
+)
+
+func validateChronyRefClock(refClock coreosconfig.ChronyRefClock, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !strings.HasPrefix(refClock.Device, "/dev/") || strings.ContainsAny(refClock.Device, " \t\r\n") {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("device"), refClock.Device, "must be a device path below /dev without whitespace"))
+	}
+	allErrs = append(allErrs, validateChronyRefClockPoll(refClock.Poll, fldPath.Child("poll"))...)
+	allErrs = append(allErrs, validateChronyRefClockPoll(refClock.DPoll, fldPath.Child("dpoll"))...)
+	return allErrs
+}
+
+func validateChronyRefClockPoll(poll *int32, fldPath *field.Path) field.ErrorList {
+	if poll != nil && (*poll < minChronyRefClockPoll || *poll > maxChronyRefClockPoll) {
+		return field.ErrorList{field.Invalid(fldPath, *poll, fmt.Sprintf("must be between %d and %d", minChronyRefClockPoll, maxChronyRefClockPoll))}
+	}
+	return nil
+}
+
+var (
+	// allowedSysctlPrefixes are the kernel parameter namespaces which may be configured.
+	allowedSysctlPrefixes = []string{"net.", "vm.", "kernel.", "fs."}
+	// sysctlKeyRegex matches kernel parameter names, allowing both '.' and '/' as separators.
+	sysctlKeyRegex = regexp.MustCompile(`^[a-z0-9_\-]+([./][a-zA-Z0-9_\-]+)+$`)
+	// dangerousSysctlValues maps kernel parameters to values which break Kubernetes nodes or weaken their security.
+	dangerousSysctlValues = map[string]map[string]string{
+		"net.ipv4.ip_forward":                 {"0": "disables pod networking"},
+		"net.ipv6.conf.all.forwarding":        {"0": "disables pod networking"},
+		"net.bridge.bridge-nf-call-iptables":  {"0": "bypasses kube-proxy for bridged traffic"},
+		"net.bridge.bridge-nf-call-ip6tables": {"0": "bypasses kube-proxy for bridged traffic"},
+		"kernel.modules_disabled":             {"1": "prevents loading kernel modules until the next reboot"},
+		"kernel.randomize_va_space":           {"0": "disables address space layout randomization"},
+		"vm.overcommit_memory":                {"2": "makes the kubelet and containers fail memory allocations"},
+		"fs.protected_hardlinks":              {"0": "allows hardlink-based privilege escalation"},
+		"fs.protected_symlinks":               {"0": "allows symlink-based privilege escalation"},
+	}
+)
+
+func validateSysctlConfig(config *coreosconfig.SysctlConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	validProfiles := sets.New(coreosconfig.SysctlProfileNetworkHeavy, coreosconfig.SysctlProfileHardened)
+	for i, profile := range config.Profiles {
+		if !validProfiles.Has(profile) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("profiles").Index(i), profile, sets.List(validProfiles)))
+		}
+	}
+
+	for _, key := range sets.List(sets.KeySet(config.Settings)) {
+		value := config.Settings[key]
+		keyPath := fldPath.Child("settings").Key(key)
+
+		if !hasAllowedSysctlPrefix(key) {
+			allErrs = append(allErrs, field.Forbidden(keyPath, fmt.Sprintf("only kernel parameters starting with %s are allowed", strings.Join(allowedSysctlPrefixes, ", "))))
+			continue
+		}
+		if !sysctlKeyRegex.MatchString(key) {
+			allErrs = append(allErrs, field.Invalid(keyPath, key, "must be a valid kernel parameter name"))
+			continue
+		}
+
+		normalizedKey := strings.ReplaceAll(key, "/", ".")
+		normalizedValue := strings.TrimSpace(value)
+		if normalizedValue == "" {
+			allErrs = append(allErrs, field.Required(keyPath, "a value is required"))
+		} else if strings.ContainsAny(value, "\n\r") {
+			allErrs = append(allErrs, field.Invalid(keyPath, value, "must not contain line breaks"))
+		} else if reason, ok := dangerousSysctlValues[normalizedKey][normalizedValue]; ok {
+			allErrs = append(allErrs, field.Forbidden(keyPath, fmt.Sprintf("value %q %s", normalizedValue, reason)))
+		}
+	}
+
+	return allErrs
+}
+
+func hasAllowedSysctlPrefix(key string) bool {
+	for _, prefix := range allowedSysctlPrefixes {
+		if strings.HasPrefix(key, prefix) || strings.HasPrefix(key, strings.ReplaceAll(prefix, ".", "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotterRegex matches the names of containerd snapshotter plugins.
+var snapshotterRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9\-]*$`)
+
+// registryHostRegex matches registry hosts as used for the hosts.toml files, i.e. a host name or IP address with an
+// optional port.
+var registryHostRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-.]*[a-zA-Z0-9])?(:[0-9]{1,5})?$`)
+
+func validateContainerdConfig(config *coreosconfig.ContainerdConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config.ConfigVersion != nil && *config.ConfigVersion != 2 && *config.ConfigVersion != 3 {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("configVersion"), *config.ConfigVersion, []string{"2", "3"}))
+	}
+
+	if config.SandboxImage != nil && strings.TrimSpace(*config.SandboxImage) == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("sandboxImage"), "must not be empty if set"))
+	}
+
+	if config.Snapshotter != nil && !snapshotterRegex.MatchString(*config.Snapshotter) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("snapshotter"), *config.Snapshotter, "must be a valid snapshotter name"))
+	}
+
+	registries := sets.New[string]()
+	for i, registryAuth := range config.RegistryAuth {
+		idxPath := fldPath.Child("registryAuth").Index(i)
+
+		if !registryHostRegex.MatchString(registryAuth.Registry) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("registry"), registryAuth.Registry, "must be a registry host, optionally including the port"))
+		} else if registries.Has(registryAuth.Registry) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("registry"), registryAuth.Registry))
+		}
+		registries.Insert(registryAuth.Registry)
+
+		if registryAuth.SecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("secretRef", "name"), "must reference a secret"))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(registryAuth.SecretRef.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("secretRef", "name"), registryAuth.SecretRef.Name, msg))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateContainerdRegistryAuth makes sure that registry credentials are only configured together with the given
+// effective containerd config version 3. The credentials are written to a configuration file imported by the main
+// configuration file, and containerd 1.x replaces the whole CRI plugin configuration of the main configuration file with
+// the one of the import, including the registry config path and the cgroup driver set by gardener-node-agent. It is meant
+// for the merged config, as the version might be configured in the extension config only.
+func ValidateContainerdRegistryAuth(config *coreosconfig.ContainerdConfig, configVersion int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config != nil && len(config.RegistryAuth) > 0 && configVersion != 3 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("registryAuth"), fmt.Sprintf("registry credentials require containerd config version 3, but version %d is used", configVersion)))
+	}
+	return allErrs
+}
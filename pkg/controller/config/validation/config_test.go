@@ -0,0 +1,752 @@
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+var _ = Describe("ExtensionConfig validation", func() {
+	var (
+		config *coreosconfig.ExtensionConfig
+	)
+
+	BeforeEach(func() {
+		config = &coreosconfig.ExtensionConfig{
+			NTP: &coreosconfig.NTPConfig{
+				Daemon: coreosconfig.SystemdTimesyncd,
+			},
+		}
+	})
+
+	It("should allow valid config", func() {
+		Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+	})
+
+	It("should fail with incorrect daemon name", func() {
+		config.NTP.Daemon = "foo"
+		errs := ValidateExtensionConfig(config)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+		Expect(errs[0].Field).To(Equal("ntp.daemon"))
+	})
+
+	It("should only validate the sections if the daemon is not set", func() {
+		config.NTP = &coreosconfig.NTPConfig{
+			NTPD:                  &coreosconfig.NTPDConfig{Servers: []string{"pool.ntp.org iburst"}},
+			RequireAuthentication: ptr.To(true),
+		}
+		errs := ValidateExtensionConfig(config)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+		Expect(errs[0].Field).To(Equal("ntp.ntpd.servers[0]"))
+	})
+
+	Describe("#ValidateNTPDaemonConfigured", func() {
+		It("should require the ntpd config with daemon ntpd", func() {
+			errs := ValidateNTPDaemonConfigured(&coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.NTPD}, field.NewPath("ntp"))
+			Expect(errs).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("ntp.ntpd")}))))
+		})
+
+		It("should allow ntpd with its config", func() {
+			Expect(ValidateNTPDaemonConfigured(&coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.NTPD, NTPD: &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}}}, field.NewPath("ntp"))).To(BeEmpty())
+		})
+
+		It("should allow daemons falling back to the config of the image without their config", func() {
+			Expect(ValidateNTPDaemonConfigured(&coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.SystemdTimesyncd}, field.NewPath("ntp"))).To(BeEmpty())
+			Expect(ValidateNTPDaemonConfigured(&coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.Chrony}, field.NewPath("ntp"))).To(BeEmpty())
+		})
+
+		It("should allow a disabled time synchronization without config", func() {
+			Expect(ValidateNTPDaemonConfigured(&coreosconfig.NTPConfig{Enabled: ptr.To(false), Daemon: coreosconfig.NTPD}, field.NewPath("ntp"))).To(BeEmpty())
+		})
+	})
+
+	Describe("#ValidateContainerdRegistryAuth", func() {
+		var containerd *coreosconfig.ContainerdConfig
+
+		BeforeEach(func() {
+			containerd = &coreosconfig.ContainerdConfig{
+				RegistryAuth: []coreosconfig.RegistryAuth{
+					{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"}},
+				},
+			}
+		})
+
+		It("should allow registry credentials with config version 3", func() {
+			Expect(ValidateContainerdRegistryAuth(containerd, 3, field.NewPath("containerd"))).To(BeEmpty())
+		})
+
+		It("should forbid registry credentials with config version 2", func() {
+			Expect(ValidateContainerdRegistryAuth(containerd, 2, field.NewPath("containerd"))).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("containerd.registryAuth")})),
+			))
+		})
+
+		It("should allow config version 2 without registry credentials", func() {
+			Expect(ValidateContainerdRegistryAuth(&coreosconfig.ContainerdConfig{}, 2, field.NewPath("containerd"))).To(BeEmpty())
+		})
+	})
+
+	Describe("#ValidateProviderConfigSecretRefs", func() {
+		It("should allow secrets referenced by the shoot", func() {
+			config.NTP = &coreosconfig.NTPConfig{NTPD: &coreosconfig.NTPDConfig{
+				Authentication: &coreosconfig.NTPDAuthentication{KeyID: 1, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "ref-ntp-key"}},
+			}}
+			config.Containerd = &coreosconfig.ContainerdConfig{
+				RegistryAuth: []coreosconfig.RegistryAuth{
+					{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"}},
+				},
+			}
+			config.WorkerPoolOverrides = []coreosconfig.WorkerPoolOverride{{
+				Pools: []string{"worker"},
+				Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{
+					RegistryAuth: []coreosconfig.RegistryAuth{
+						{Registry: "registry.example.org", SecretRef: corev1.LocalObjectReference{Name: "ref-worker-credentials"}},
+					},
+				}},
+			}}
+			Expect(ValidateProviderConfigSecretRefs(config)).To(BeEmpty())
+		})
+
+		It("should forbid other secrets of the namespace", func() {
+			config.NTP = &coreosconfig.NTPConfig{NTPD: &coreosconfig.NTPDConfig{
+				Authentication: &coreosconfig.NTPDAuthentication{KeyID: 1, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "cloudprovider"}},
+			}}
+			config.Containerd = &coreosconfig.ContainerdConfig{
+				RegistryAuth: []coreosconfig.RegistryAuth{
+					{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "cloudprovider"}},
+				},
+			}
+			config.WorkerPoolOverrides = []coreosconfig.WorkerPoolOverride{{
+				Pools: []string{"worker"},
+				Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{
+					RegistryAuth: []coreosconfig.RegistryAuth{
+						{Registry: "registry.example.org", SecretRef: corev1.LocalObjectReference{Name: "foo"}},
+					},
+				}},
+			}}
+			Expect(ValidateProviderConfigSecretRefs(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.authentication.secretRef.name"), "BadValue": Equal("cloudprovider")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("containerd.registryAuth[0].secretRef.name"), "BadValue": Equal("cloudprovider")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("workerPoolOverrides[0].config.containerd.registryAuth[0].secretRef.name"), "BadValue": Equal("foo")})),
+			))
+		})
+	})
+
+	It("should fail with daemon systemd-timesyncd and ntpd config set", func() {
+		config.NTP.Daemon = coreosconfig.SystemdTimesyncd
+		config.NTP.NTPD = &coreosconfig.NTPDConfig{Servers: []string{"foo.bar"}}
+		errs := ValidateExtensionConfig(config)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+		Expect(errs[0].Field).To(Equal("ntp.ntpd"))
+		Expect(errs[0].Detail).To(Equal("ntpd config is only allowed with daemon ntpd"))
+	})
+
+	Context("ntpd", func() {
+		BeforeEach(func() {
+			config.NTP.Daemon = coreosconfig.NTPD
+		})
+
+		It("should allow sources with options, restrict lines and a drift file", func() {
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{
+				Sources: []coreosconfig.NTPDSource{
+					{Address: "ntp1.example.com", Prefer: true, MinPoll: ptr.To[int32](4), MaxPoll: ptr.To[int32](10), Key: ptr.To[int32](1)},
+					{Address: "pool.ntp.org", Pool: true},
+				},
+				Authentication: &coreosconfig.NTPDAuthentication{KeyID: 1, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "ntp-key"}},
+				Restrict:       []string{"default kod nomodify notrap nopeer noquery", "127.0.0.1"},
+				DriftFile:      ptr.To("/var/lib/ntp/drift"),
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid sources, restrict lines and drift file", func() {
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{
+				Sources: []coreosconfig.NTPDSource{
+					{Address: "pool.ntp.org iburst", MinPoll: ptr.To[int32](2), MaxPoll: ptr.To[int32](18)},
+					{Address: "ntp1.example.com", MinPoll: ptr.To[int32](10), MaxPoll: ptr.To[int32](6), Key: ptr.To[int32](2)},
+				},
+				Restrict:  []string{"default\nrestrict 10.0.0.0"},
+				DriftFile: ptr.To("ntp.drift"),
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.sources[0].address")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.sources[0].minPoll")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.sources[0].maxPoll")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.sources[1].maxPoll")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.sources[1].key")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.restrict[0]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.driftFile")})),
+			))
+		})
+
+		It("should fail without servers and sources", func() {
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+			Expect(errs[0].Field).To(Equal("ntp.ntpd.servers"))
+		})
+
+		It("should allow host names, IP addresses and interface names", func() {
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{
+				Servers:    []string{"ntp1.example.com", "10.0.0.1", "fd00::1", "NTP2.example.com."},
+				Interfaces: []string{"eth0", "ens5.100", "10.0.0.2"},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid or duplicate servers and interfaces", func() {
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{
+				Servers:    []string{"pool.ntp.org iburst", "ntp_1.example.com", "ntp1.example.com", "NTP1.example.com."},
+				Sources:    []coreosconfig.NTPDSource{{Address: "ntp1.example.com"}},
+				Interfaces: []string{"eth0", "eth0", "a-very-long-interface-name", "eth0:1", "eth/0", ""},
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.servers[0]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.servers[1]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("ntp.ntpd.servers[3]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("ntp.ntpd.sources[0].address")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("ntp.ntpd.interfaces[1]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.interfaces[2]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.interfaces[3]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.interfaces[4]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.interfaces[5]")})),
+			))
+		})
+	})
+
+	Context("timesyncd", func() {
+		It("should allow valid settings", func() {
+			config.NTP.Timesyncd = &coreosconfig.TimesyncdConfig{
+				Servers:         []string{"ntp1.example.com", "10.0.0.1"},
+				FallbackServers: []string{"pool.ntp.org"},
+				PollIntervalMin: &metav1.Duration{Duration: 16 * time.Second},
+				PollIntervalMax: &metav1.Duration{Duration: 30 * time.Minute},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with daemon ntpd and timesyncd config set", func() {
+			config.NTP.Daemon = coreosconfig.NTPD
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{Servers: []string{"foo.bar"}}
+			config.NTP.Timesyncd = &coreosconfig.TimesyncdConfig{Servers: []string{"foo.bar"}}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+			Expect(errs[0].Field).To(Equal("ntp.timesyncd"))
+			Expect(errs[0].Detail).To(Equal("systemd-timesyncd config is only allowed with daemon systemd-timesyncd"))
+		})
+
+		It("should fail with invalid settings", func() {
+			config.NTP.Timesyncd = &coreosconfig.TimesyncdConfig{
+				FallbackServers: []string{"pool.ntp.org iburst"},
+				PollIntervalMin: &metav1.Duration{Duration: 8 * time.Second},
+				PollIntervalMax: &metav1.Duration{Duration: 30500 * time.Millisecond},
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("ntp.timesyncd.servers")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.timesyncd.fallbackServers[0]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.timesyncd.pollIntervalMin")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.timesyncd.pollIntervalMax")})),
+			))
+		})
+
+		It("should fail with duplicate servers", func() {
+			config.NTP.Timesyncd = &coreosconfig.TimesyncdConfig{
+				Servers:         []string{"ntp1.example.com", "ntp1.example.com"},
+				FallbackServers: []string{"ntp1.example.com"},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeDuplicate))
+			Expect(errs[0].Field).To(Equal("ntp.timesyncd.servers[1]"))
+		})
+
+		It("should fail if the maximum poll interval is smaller than the minimum", func() {
+			config.NTP.Timesyncd = &coreosconfig.TimesyncdConfig{
+				Servers:         []string{"ntp1.example.com"},
+				PollIntervalMin: &metav1.Duration{Duration: 64 * time.Second},
+				PollIntervalMax: &metav1.Duration{Duration: 32 * time.Second},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errs[0].Field).To(Equal("ntp.timesyncd.pollIntervalMax"))
+		})
+	})
+
+	Context("chrony", func() {
+		It("should allow valid settings", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{
+				Servers:  []coreosconfig.ChronySource{{Address: "ntp1.example.com"}},
+				Pools:    []coreosconfig.ChronySource{{Address: "pool.ntp.org"}},
+				MakeStep: &coreosconfig.ChronyMakeStep{Threshold: metav1.Duration{Duration: 100 * time.Millisecond}, Limit: -1},
+				RTCSync:  ptr.To(false),
+				Allow:    []string{"10.0.0.0/8", "fd00::/8"},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with daemon systemd-timesyncd and chrony config set", func() {
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{Servers: []coreosconfig.ChronySource{{Address: "foo.bar"}}}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+			Expect(errs[0].Field).To(Equal("ntp.chrony"))
+			Expect(errs[0].Detail).To(Equal("chrony config is only allowed with daemon chrony"))
+		})
+
+		It("should fail with invalid settings", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{
+				Pools:    []coreosconfig.ChronySource{{Address: "pool.ntp.org iburst"}},
+				MakeStep: &coreosconfig.ChronyMakeStep{Limit: 0},
+				Allow:    []string{"10.0.0.1"},
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.chrony.pools[0].address")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.chrony.makeStep.threshold")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.chrony.makeStep.limit")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.chrony.allow[0]")})),
+			))
+		})
+
+		It("should fail without servers and pools", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+			Expect(errs[0].Field).To(Equal("ntp.chrony.servers"))
+		})
+
+		It("should allow reference clocks without servers and pools", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{
+				RefClocks: []coreosconfig.ChronyRefClock{{Device: "/dev/ptp_hyperv", Poll: ptr.To[int32](3), DPoll: ptr.To[int32](-2)}},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid reference clocks", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{
+				RefClocks: []coreosconfig.ChronyRefClock{{Device: "ptp0", Poll: ptr.To[int32](25), DPoll: ptr.To[int32](-7)}},
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.chrony.refClocks[0].device")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.chrony.refClocks[0].poll")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.chrony.refClocks[0].dpoll")})),
+			))
+		})
+	})
+
+	Context("waitForSync", func() {
+		It("should allow a timeout", func() {
+			config.NTP.WaitForSync = &coreosconfig.NTPWaitForSync{Enabled: true, Timeout: &metav1.Duration{Duration: 5 * time.Minute}}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with an invalid timeout", func() {
+			for _, timeout := range []time.Duration{0, 1500 * time.Millisecond, time.Hour} {
+				config.NTP.WaitForSync = &coreosconfig.NTPWaitForSync{Enabled: true, Timeout: &metav1.Duration{Duration: timeout}}
+				errs := ValidateExtensionConfig(config)
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+				Expect(errs[0].Field).To(Equal("ntp.waitForSync.timeout"))
+			}
+		})
+	})
+
+	Context("authentication", func() {
+		It("should allow chrony with NTS and a custom CA", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.RequireAuthentication = ptr.To(true)
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{
+				Servers:                []coreosconfig.ChronySource{{Address: "nts.example.com", NTS: true}},
+				NTSTrustedCertificates: ptr.To(generateCACertificate()),
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should allow ntpd with key-based authentication", func() {
+			config.NTP.Daemon = coreosconfig.NTPD
+			config.NTP.RequireAuthentication = ptr.To(true)
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{
+				Servers:        []string{"ntp.example.com"},
+				Authentication: &coreosconfig.NTPDAuthentication{KeyID: 1, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "ntp-key"}},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail if systemd-timesyncd is required to authenticate", func() {
+			config.NTP.RequireAuthentication = ptr.To(true)
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+			Expect(errs[0].Field).To(Equal("ntp.requireAuthentication"))
+		})
+
+		It("should fail if chrony sources without NTS are configured", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.RequireAuthentication = ptr.To(true)
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{
+				Servers: []coreosconfig.ChronySource{{Address: "nts.example.com", NTS: true}},
+				Pools:   []coreosconfig.ChronySource{{Address: "pool.ntp.org"}},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errs[0].Field).To(Equal("ntp.chrony.pools[0].nts"))
+		})
+
+		It("should fail if ntpd sources without key are configured", func() {
+			config.NTP.Daemon = coreosconfig.NTPD
+			config.NTP.RequireAuthentication = ptr.To(true)
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{
+				Sources:        []coreosconfig.NTPDSource{{Address: "ntp.example.com"}},
+				Authentication: &coreosconfig.NTPDAuthentication{KeyID: 1, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "ntp-key"}},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+			Expect(errs[0].Field).To(Equal("ntp.ntpd.sources[0].key"))
+		})
+
+		It("should fail if ntpd is required to authenticate without key", func() {
+			config.NTP.Daemon = coreosconfig.NTPD
+			config.NTP.RequireAuthentication = ptr.To(true)
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+			Expect(errs[0].Field).To(Equal("ntp.ntpd.authentication"))
+		})
+
+		It("should fail with invalid authentication settings", func() {
+			config.NTP.Daemon = coreosconfig.NTPD
+			config.NTP.NTPD = &coreosconfig.NTPDConfig{
+				Servers:        []string{"ntp.example.com"},
+				Authentication: &coreosconfig.NTPDAuthentication{KeyID: 0, Type: "SHA512"},
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("ntp.ntpd.authentication.keyID")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("ntp.ntpd.authentication.type")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("ntp.ntpd.authentication.secretRef.name")})),
+			))
+		})
+
+		It("should fail with an invalid NTS CA", func() {
+			config.NTP.Daemon = coreosconfig.Chrony
+			config.NTP.Chrony = &coreosconfig.ChronyConfig{
+				Servers:                []coreosconfig.ChronySource{{Address: "nts.example.com", NTS: true}},
+				NTSTrustedCertificates: ptr.To(generateCACertificate() + "foo"),
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
+			Expect(errs[0].Field).To(Equal("ntp.chrony.ntsTrustedCertificates"))
+		})
+	})
+
+	Context("containerd", func() {
+		It("should allow valid settings", func() {
+			config.Containerd = &coreosconfig.ContainerdConfig{
+				ConfigVersion: ptr.To[int32](3),
+				SandboxImage:  ptr.To("registry.k8s.io/pause:3.10"),
+				Snapshotter:   ptr.To("overlayfs"),
+				RegistryAuth: []coreosconfig.RegistryAuth{
+					{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"}},
+					{Registry: "10.0.0.1:5000", SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"}},
+				},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid registry credentials", func() {
+			config.Containerd = &coreosconfig.ContainerdConfig{
+				RegistryAuth: []coreosconfig.RegistryAuth{
+					{Registry: "https://registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "foo"}},
+					{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "foo"}},
+					{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "Foo_Bar"}},
+					{Registry: "registry.example.org"},
+				},
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("containerd.registryAuth[0].registry")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("containerd.registryAuth[2].registry")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("containerd.registryAuth[2].secretRef.name")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("containerd.registryAuth[3].secretRef.name")})),
+			))
+		})
+
+		It("should fail with invalid settings", func() {
+			config.Containerd = &coreosconfig.ContainerdConfig{
+				ConfigVersion: ptr.To[int32](1),
+				SandboxImage:  ptr.To(""),
+				Snapshotter:   ptr.To("Overlay FS"),
+			}
+			Expect(ValidateExtensionConfig(config)).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("containerd.configVersion")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("containerd.sandboxImage")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("containerd.snapshotter")})),
+			))
+		})
+	})
+
+	Context("sysctl", func() {
+		It("should allow known profiles and settings", func() {
+			config.Sysctl = &coreosconfig.SysctlConfig{
+				Profiles: []coreosconfig.SysctlProfile{coreosconfig.SysctlProfileHardened, coreosconfig.SysctlProfileNetworkHeavy},
+				Settings: map[string]string{
+					"net.core.somaxconn":          "4096",
+					"vm.max_map_count":            "262144",
+					"kernel/pid_max":              "4194304",
+					"fs.inotify.max_user_watches": "524288",
+				},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with an unknown profile", func() {
+			config.Sysctl = &coreosconfig.SysctlConfig{Profiles: []coreosconfig.SysctlProfile{"foo"}}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+			Expect(errs[0].Field).To(Equal("sysctl.profiles[0]"))
+		})
+
+		It("should fail with keys outside of the allowed namespaces", func() {
+			config.Sysctl = &coreosconfig.SysctlConfig{Settings: map[string]string{"dev.raid.speed_limit_max": "1000"}}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+			Expect(errs[0].Field).To(Equal("sysctl.settings[dev.raid.speed_limit_max]"))
+		})
+
+		It("should fail with invalid keys and values", func() {
+			config.Sysctl = &coreosconfig.SysctlConfig{Settings: map[string]string{
+				"net.":               "1",
+				"net.core.somaxconn": "",
+				"vm.swappiness":      "10\nkernel.modules_disabled = 1",
+			}}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("sysctl.settings[net.]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("sysctl.settings[net.core.somaxconn]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("sysctl.settings[vm.swappiness]")})),
+			))
+		})
+
+		It("should fail with dangerous values", func() {
+			config.Sysctl = &coreosconfig.SysctlConfig{Settings: map[string]string{
+				"net.ipv4.ip_forward":     "0",
+				"kernel/modules_disabled": " 1 ",
+			}}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("sysctl.settings[net.ipv4.ip_forward]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("sysctl.settings[kernel/modules_disabled]")})),
+			))
+		})
+	})
+
+	Context("type overrides", func() {
+		It("should allow overrides with settings", func() {
+			config.TypeOverrides = []coreosconfig.TypeOverride{{
+				Types:  []string{"flatcar-lts", "flatcar-stable"},
+				Config: coreosconfig.ExtensionConfig{NTP: &coreosconfig.NTPConfig{Daemon: coreosconfig.Chrony}},
+			}}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid types, nested overrides and invalid settings", func() {
+			config.TypeOverrides = []coreosconfig.TypeOverride{
+				{
+					Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{ConfigVersion: ptr.To[int32](4)}},
+				},
+				{
+					Types: []string{"flatcar", "", "flatcar"},
+					Config: coreosconfig.ExtensionConfig{
+						Policy:        &coreosconfig.PolicyConfig{},
+						TypeOverrides: []coreosconfig.TypeOverride{},
+					},
+				},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("typeOverrides[0].types")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("typeOverrides[0].config.containerd.configVersion")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("typeOverrides[1].types[1]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("typeOverrides[1].types[2]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("typeOverrides[1].config.policy")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("typeOverrides[1].config.typeOverrides")})),
+			))
+		})
+	})
+
+	Context("worker pool overrides", func() {
+		It("should allow overrides with settings", func() {
+			config.WorkerPoolOverrides = []coreosconfig.WorkerPoolOverride{{
+				Pools:  []string{"ci", "build"},
+				Config: coreosconfig.ExtensionConfig{EnableDocker: ptr.To(true)},
+			}}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid pools, nested overrides and invalid settings", func() {
+			config.WorkerPoolOverrides = []coreosconfig.WorkerPoolOverride{
+				{
+					Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{ConfigVersion: ptr.To[int32](4)}},
+				},
+				{
+					Pools: []string{"ci", "", "ci"},
+					Config: coreosconfig.ExtensionConfig{
+						ImageVersionRules:   []coreosconfig.ImageVersionRule{},
+						WorkerPoolOverrides: []coreosconfig.WorkerPoolOverride{},
+					},
+				},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("workerPoolOverrides[0].pools")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("workerPoolOverrides[0].config.containerd.configVersion")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("workerPoolOverrides[1].pools[1]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("workerPoolOverrides[1].pools[2]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("workerPoolOverrides[1].config.imageVersionRules")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("workerPoolOverrides[1].config.workerPoolOverrides")})),
+			))
+		})
+	})
+
+	Context("profiles", func() {
+		It("should allow profiles with settings and the selection of a profile", func() {
+			config.Profiles = []coreosconfig.ConfigProfile{
+				{Name: "hardened", Config: coreosconfig.ExtensionConfig{Sysctl: &coreosconfig.SysctlConfig{Profiles: []coreosconfig.SysctlProfile{coreosconfig.SysctlProfileHardened}}}},
+				{Name: "legacy-docker", Config: coreosconfig.ExtensionConfig{EnableDocker: ptr.To(true)}},
+			}
+			config.Profile = "hardened"
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid names, an unknown selection and invalid settings", func() {
+			config.Profiles = []coreosconfig.ConfigProfile{
+				{Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{ConfigVersion: ptr.To[int32](4)}}},
+				{Name: "Legacy_Docker"},
+				{Name: "performance", Config: coreosconfig.ExtensionConfig{Profile: "performance"}},
+				{Name: "performance"},
+			}
+			config.Profile = "hardened"
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("profiles[0].name")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("profiles[0].config.containerd.configVersion")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("profiles[1].name")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("profiles[2].config.profile")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("profiles[3].name")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("profile")})),
+			))
+		})
+	})
+
+	Context("image version rules", func() {
+		It("should allow rules with known customizations", func() {
+			config.ImageVersionRules = []coreosconfig.ImageVersionRule{
+				{Versions: "< 3815", Disable: []coreosconfig.Customization{coreosconfig.CustomizationMaskSysupdate}},
+				{Versions: ">= 3975.2.0, < 4000", Enable: []coreosconfig.Customization{coreosconfig.CustomizationLogrotatePath}},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with invalid constraints and customizations", func() {
+			config.ImageVersionRules = []coreosconfig.ImageVersionRule{
+				{},
+				{
+					Versions: "latest",
+					Enable:   []coreosconfig.Customization{coreosconfig.CustomizationLogrotatePath, "foo"},
+					Disable:  []coreosconfig.Customization{coreosconfig.CustomizationLogrotatePath},
+				},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("imageVersionRules[0].versions")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("imageVersionRules[0]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("imageVersionRules[1].versions")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("imageVersionRules[1].enable[1]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("imageVersionRules[1].disable[0]")})),
+			))
+		})
+	})
+
+	Context("policy", func() {
+		It("should allow paths of fields and map entries", func() {
+			config.Policy = &coreosconfig.PolicyConfig{
+				LockedFields: []string{"enableDocker", "ntp.ntpd.servers", "sysctl.settings[vm.max_map_count]", "containerd"},
+				AllowedValues: []coreosconfig.AllowedValues{
+					{Field: "ntp.daemon", Values: []string{"ntpd", "chrony"}},
+					{Field: "sysctl.profiles", Values: []string{"hardened"}},
+					{Field: "containerd.configVersion", Values: []string{"2"}},
+				},
+			}
+			Expect(ValidateExtensionConfig(config)).To(BeEmpty())
+		})
+
+		It("should fail with unknown or invalid paths", func() {
+			config.Policy = &coreosconfig.PolicyConfig{
+				LockedFields: []string{"enableDockr", "ntp.ntpd.", "ntp[foo]", "sysctl.settings[vm.max_map_count", "", "enableDocker", "enableDocker"},
+				AllowedValues: []coreosconfig.AllowedValues{
+					{Field: "ntp.ntpd", Values: []string{"foo"}},
+					{Field: "enableDocker", Values: []string{"false"}},
+					{Field: "ntp.daemon"},
+				},
+			}
+			errs := ValidateExtensionConfig(config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("policy.lockedFields[0]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("policy.lockedFields[1]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("policy.lockedFields[2]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("policy.lockedFields[3]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("policy.lockedFields[4]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeDuplicate), "Field": Equal("policy.lockedFields[6]")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeInvalid), "Field": Equal("policy.allowedValues[0].field")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("policy.allowedValues[1].field")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeRequired), "Field": Equal("policy.allowedValues[2].values")})),
+			))
+		})
+	})
+})
+
+func generateCACertificate() string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nts-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
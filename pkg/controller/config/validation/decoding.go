@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// StrictDecodingErrors converts the errors of a strict decoder, i.e. unknown and duplicate fields, into field errors.
+// It returns nil if the given error is not a strict decoding error.
+func StrictDecodingErrors(err error) field.ErrorList {
+	strictErr, ok := runtime.AsStrictDecodingError(err)
+	if !ok {
+		return nil
+	}
+
+	allErrs := field.ErrorList{}
+	for _, err := range strictErr.Errors() {
+		message := err.Error()
+		if name, ok := strings.CutPrefix(message, "unknown field "); ok {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath(strings.Trim(name, `"`)), "unknown field"))
+		} else if name, ok := strings.CutPrefix(message, "duplicate field "); ok {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath(strings.Trim(name, `"`)), "duplicate field"))
+		} else {
+			// Errors of the YAML decoder, e.g. duplicate keys, do not name the field in a parsable way.
+			allErrs = append(allErrs, field.Invalid(field.NewPath(""), nil, message))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,341 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+)
+
+// extensionConfigType is used to resolve the paths of the policy, which use the JSON names of the fields. They are the
+// same in all versions.
+var extensionConfigType = reflect.TypeFor[v1beta1.ExtensionConfig]()
+
+// extendingFields are the fields, which add to the values of another field, e.g. further time sources next to the
+// servers. They may not be set if the field they extend is locked.
+var extendingFields = map[string]string{
+	"ntp.ntpd.sources": "ntp.ntpd.servers",
+	"ntp.chrony.pools": "ntp.chrony.servers",
+}
+
+// daemonSectionPaths are the paths of the sections of the NTP daemons, which are dropped if the provider config switches
+// the daemon.
+var daemonSectionPaths = map[string]func(*coreosconfig.NTPConfig) bool{
+	"ntp.ntpd":      func(ntp *coreosconfig.NTPConfig) bool { return ntp.NTPD != nil },
+	"ntp.timesyncd": func(ntp *coreosconfig.NTPConfig) bool { return ntp.Timesyncd != nil },
+	"ntp.chrony":    func(ntp *coreosconfig.NTPConfig) bool { return ntp.Chrony != nil },
+}
+
+// ValidateProviderConfigPolicy checks the given raw provider config of a shoot against the policy of the given extension
+// config. Explicit nulls and replaced objects count as set, since they clear the field of the extension config together
+// with the locked fields below. The same holds for switching the NTP daemon, which drops the daemon sections of the
+// extension config. The settings of worker pool overrides are checked in the same way as the ones at the top level.
+func ValidateProviderConfigPolicy(raw []byte, config *coreosconfig.ExtensionConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var obj map[string]any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return append(allErrs, field.Invalid(field.NewPath(""), nil, err.Error()))
+	}
+
+	for _, name := range []string{"policy", "typeOverrides", "imageVersionRules", "profiles"} {
+		if _, ok := obj[name]; ok {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath(name), "may only be set in the extension config"))
+		}
+	}
+	if config == nil || config.Policy == nil {
+		return allErrs
+	}
+	policy := config.Policy
+
+	locked := sets.New(policy.LockedFields...)
+	allowedValues := make(map[string][]string, len(policy.AllowedValues))
+	for _, allowed := range policy.AllowedValues {
+		allowedValues[allowed.Field] = allowed.Values
+	}
+
+	checkSettings := func(obj map[string]any, fldPath *field.Path) {
+		walkSetFields(obj, extensionConfigType, nil, fldPath, func(policyPath, fldPath *field.Path, value any) bool {
+			path := policyPath.String()
+			if locked.Has(path) {
+				allErrs = append(allErrs, field.Forbidden(fldPath, "field is locked by the extension config"))
+				return false
+			}
+			if extended, ok := extendingFields[path]; ok && locked.Has(extended) {
+				allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("field adds to the locked field %s", extended)))
+				return false
+			}
+			if replacesValue(value) {
+				if descendants := lockedDescendants(locked, path); len(descendants) > 0 {
+					allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("field clears the locked fields %s", strings.Join(descendants, ", "))))
+					return false
+				}
+			}
+			if path == "ntp.daemon" {
+				if dropped := droppedDaemonSections(config.NTP, value, locked); len(dropped) > 0 {
+					allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("switching the daemon drops the locked fields %s", strings.Join(dropped, ", "))))
+					return false
+				}
+			}
+			if values, ok := allowedValues[path]; ok {
+				allErrs = append(allErrs, validateAllowedValues(value, values, fldPath)...)
+				return false
+			}
+			return true
+		})
+	}
+
+	checkSettings(obj, nil)
+	if overrides, ok := obj["workerPoolOverrides"].([]any); ok && !locked.Has("workerPoolOverrides") {
+		for i, override := range overrides {
+			override, _ := override.(map[string]any)
+			if config, ok := override["config"].(map[string]any); ok {
+				checkSettings(config, field.NewPath("workerPoolOverrides").Index(i).Child("config"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// walkSetFields calls the given function for all fields set in the given object of the given type, parents before
+// their children. The function gets the path of the field within the object, which the policy refers to, and the path
+// of the field within the provider config. Entries of maps are visited with their key, lists are visited as a whole. The
+// children of a field are only visited if the function returns true.
+func walkSetFields(obj map[string]any, t reflect.Type, policyPath, fldPath *field.Path, visit func(policyPath, fldPath *field.Path, value any) bool) {
+	for _, name := range slices.Sorted(maps.Keys(obj)) {
+		fieldType, ok := jsonFieldType(t, name)
+		if !ok {
+			// Unknown fields are rejected or ignored by the decoder.
+			continue
+		}
+		childPolicyPath, childPath := policyPath.Child(name), fldPath.Child(name)
+
+		value := obj[name]
+		if !visit(childPolicyPath, childPath, value) {
+			continue
+		}
+		children, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		switch fieldType = indirect(fieldType); fieldType.Kind() {
+		case reflect.Struct:
+			walkSetFields(children, fieldType, childPolicyPath, childPath, visit)
+		case reflect.Map:
+			for _, key := range slices.Sorted(maps.Keys(children)) {
+				visit(childPolicyPath.Key(key), childPath.Key(key), children[key])
+			}
+		}
+	}
+}
+
+// replacesValue returns whether the given value of the provider config replaces the value of the extension config as a
+// whole when merging, i.e. it is an explicit null or an object with a patch directive.
+func replacesValue(value any) bool {
+	if value == nil {
+		return true
+	}
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	_, hasPatch := obj["$patch"]
+	_, hasRetainKeys := obj["$retainKeys"]
+	return hasPatch || hasRetainKeys
+}
+
+// lockedDescendants returns the locked fields below the field with the given path, in sorted order.
+func lockedDescendants(locked sets.Set[string], path string) []string {
+	var descendants []string
+	for _, lockedPath := range sets.List(locked) {
+		if strings.HasPrefix(lockedPath, path+".") || strings.HasPrefix(lockedPath, path+"[") {
+			descendants = append(descendants, lockedPath)
+		}
+	}
+	return descendants
+}
+
+// droppedDaemonSections returns the locked fields within the daemon sections of the given NTP config of the extension
+// config, which are dropped if the provider config sets the given daemon, in sorted order.
+func droppedDaemonSections(ntp *coreosconfig.NTPConfig, daemon any, locked sets.Set[string]) []string {
+	if ntp == nil || daemon == nil || daemon == "" || daemon == string(ntp.Daemon) {
+		return nil
+	}
+	var dropped []string
+	for _, sectionPath := range slices.Sorted(maps.Keys(daemonSectionPaths)) {
+		if !daemonSectionPaths[sectionPath](ntp) {
+			continue
+		}
+		if locked.Has(sectionPath) {
+			dropped = append(dropped, sectionPath)
+		}
+		dropped = append(dropped, lockedDescendants(locked, sectionPath)...)
+	}
+	return dropped
+}
+
+// validateAllowedValues makes sure that the given scalar value or all elements of the given list are allowed.
+func validateAllowedValues(value any, values []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if list, ok := value.([]any); ok {
+		for i, element := range list {
+			allErrs = append(allErrs, validateAllowedValues(element, values, fldPath.Index(i))...)
+		}
+		return allErrs
+	}
+
+	var formatted string
+	switch v := value.(type) {
+	case string:
+		formatted = v
+	case bool:
+		formatted = strconv.FormatBool(v)
+	case float64:
+		formatted = strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		formatted = "null"
+	default:
+		return append(allErrs, field.Invalid(fldPath, value, "must be a scalar value"))
+	}
+	if !slices.Contains(values, formatted) {
+		allErrs = append(allErrs, field.NotSupported(fldPath, formatted, values))
+	}
+
+	return allErrs
+}
+
+// validatePolicyConfig makes sure that the paths of the policy refer to fields of the extension config.
+func validatePolicyConfig(config *coreosconfig.PolicyConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	lockedFields := sets.New[string]()
+	for i, path := range config.LockedFields {
+		idxPath := fldPath.Child("lockedFields").Index(i)
+		if _, err := resolveFieldPath(path); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath, path, err.Error()))
+		}
+		if lockedFields.Has(path) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, path))
+		}
+		lockedFields.Insert(path)
+	}
+
+	allowedFields := sets.New[string]()
+	for i, allowed := range config.AllowedValues {
+		idxPath := fldPath.Child("allowedValues").Index(i)
+		t, err := resolveFieldPath(allowed.Field)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("field"), allowed.Field, err.Error()))
+		} else if !isScalarOrScalarList(t) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("field"), allowed.Field, "must refer to a scalar value or a list of scalar values"))
+		}
+		if allowedFields.Has(allowed.Field) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("field"), allowed.Field))
+		}
+		if lockedFields.Has(allowed.Field) {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("field"), "field is locked"))
+		}
+		allowedFields.Insert(allowed.Field)
+		if len(allowed.Values) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("values"), "at least one value is required, lock the field instead"))
+		}
+	}
+
+	return allErrs
+}
+
+// resolveFieldPath returns the type of the field of the extension config with the given path, e.g. ntp.ntpd.servers or
+// sysctl.settings[vm.max_map_count].
+func resolveFieldPath(path string) (reflect.Type, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path must not be empty")
+	}
+
+	t, rest := extensionConfigType, path
+	for rest != "" {
+		name := rest
+		if i := strings.IndexAny(rest, ".["); i >= 0 {
+			name, rest = rest[:i], rest[i:]
+		} else {
+			rest = ""
+		}
+
+		fieldType, ok := jsonFieldType(t, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		t = fieldType
+
+		if key, ok := strings.CutPrefix(rest, "["); ok {
+			end := strings.Index(key, "]")
+			if end < 1 {
+				return nil, fmt.Errorf("key of field %q must be enclosed in brackets", name)
+			}
+			if indirect(t).Kind() != reflect.Map {
+				return nil, fmt.Errorf("field %q is not a map", name)
+			}
+			t, rest = indirect(t).Elem(), key[end+1:]
+		}
+
+		if rest != "" {
+			if rest, ok = strings.CutPrefix(rest, "."); !ok || rest == "" {
+				return nil, fmt.Errorf("invalid path")
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// jsonFieldType returns the type of the field of the given struct type with the given JSON name. Inlined fields, i.e.
+// apiVersion and kind, are not considered.
+func jsonFieldType(t reflect.Type, name string) (reflect.Type, bool) {
+	t = indirect(t)
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		if structField.Anonymous {
+			continue
+		}
+		if jsonName, _, _ := strings.Cut(structField.Tag.Get("json"), ","); jsonName == name {
+			return structField.Type, true
+		}
+	}
+	return nil, false
+}
+
+func isScalarOrScalarList(t reflect.Type) bool {
+	t = indirect(t)
+	if t.Kind() == reflect.Slice {
+		t = indirect(t.Elem())
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+var _ = Describe("Provider config policy", func() {
+	var (
+		policy *coreosconfig.PolicyConfig
+		config *coreosconfig.ExtensionConfig
+	)
+
+	BeforeEach(func() {
+		policy = &coreosconfig.PolicyConfig{
+			LockedFields: []string{"enableDocker", "ntp.ntpd.servers", "sysctl.settings[kernel.panic]"},
+			AllowedValues: []coreosconfig.AllowedValues{
+				{Field: "ntp.daemon", Values: []string{"ntpd", "chrony"}},
+				{Field: "sysctl.profiles", Values: []string{"network-heavy"}},
+				{Field: "containerd.configVersion", Values: []string{"2"}},
+			},
+		}
+		config = &coreosconfig.ExtensionConfig{Policy: policy}
+	})
+
+	It("should allow provider configs which only set unrestricted fields and allowed values", func() {
+		Expect(ValidateProviderConfigPolicy([]byte(`{"ntp":{"enabled":true,"daemon":"chrony","ntpd":{"interfaces":["eth0"]}},"sysctl":{"profiles":["network-heavy"],"settings":{"kernel.pid_max":"65536"}},"containerd":{"configVersion":2}}`), config)).To(BeEmpty())
+	})
+
+	It("should allow any provider config without policy", func() {
+		Expect(ValidateProviderConfigPolicy([]byte(`{"enableDocker":true,"ntp":{"daemon":"chrony"}}`), nil)).To(BeEmpty())
+	})
+
+	It("should forbid to set or clear locked fields", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"enableDocker":true,"ntp":{"ntpd":null},"sysctl":{"settings":{"kernel.panic":null}}}`), &coreosconfig.ExtensionConfig{Policy: &coreosconfig.PolicyConfig{
+			LockedFields: []string{"enableDocker", "ntp.ntpd", "sysctl.settings[kernel.panic]"},
+		}})
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("enableDocker")})),
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("ntp.ntpd")})),
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("sysctl.settings[kernel.panic]")})),
+		))
+	})
+
+	It("should forbid to set subfields of locked fields", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"ntp":{"ntpd":{"servers":["ntp.example.com"]}}}`), config)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("ntp.ntpd.servers")})),
+		))
+	})
+
+	It("should forbid to clear ancestors of locked fields", func() {
+		for _, raw := range []string{`{"ntp":null}`, `{"ntp":{"ntpd":null}}`, `{"ntp":{"ntpd":{"$patch":"replace","interfaces":["eth0"]}}}`} {
+			errs := ValidateProviderConfigPolicy([]byte(raw), config)
+			Expect(errs).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Detail": Equal("field clears the locked fields ntp.ntpd.servers")})),
+			), raw)
+		}
+	})
+
+	It("should forbid to clear ancestors of locked fields in worker pool overrides", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"workerPoolOverrides":[{"pools":["ci"],"config":{"sysctl":{"settings":null}}}]}`), config)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("workerPoolOverrides[0].config.sysctl.settings")})),
+		))
+	})
+
+	It("should forbid to add to locked fields", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"ntp":{"ntpd":{"sources":[{"address":"ntp.example.com"}]}}}`), config)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("ntp.ntpd.sources"), "Detail": Equal("field adds to the locked field ntp.ntpd.servers")})),
+		))
+	})
+
+	It("should forbid to switch the daemon if it drops the section with locked fields", func() {
+		config.NTP = &coreosconfig.NTPConfig{Daemon: coreosconfig.NTPD, NTPD: &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}}}
+
+		errs := ValidateProviderConfigPolicy([]byte(`{"ntp":{"daemon":"chrony","chrony":{"servers":[{"address":"time.example.com"}]}}}`), config)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("ntp.daemon"), "Detail": Equal("switching the daemon drops the locked fields ntp.ntpd.servers")})),
+		))
+		errs = ValidateProviderConfigPolicy([]byte(`{"workerPoolOverrides":[{"pools":["ci"],"config":{"ntp":{"daemon":"chrony"}}}]}`), config)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("workerPoolOverrides[0].config.ntp.daemon")})),
+		))
+	})
+
+	It("should allow to keep the daemon with locked fields in its section", func() {
+		config.NTP = &coreosconfig.NTPConfig{Daemon: coreosconfig.NTPD, NTPD: &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}}}
+
+		Expect(ValidateProviderConfigPolicy([]byte(`{"ntp":{"daemon":"ntpd","ntpd":{"interfaces":["eth0"]}}}`), config)).To(BeEmpty())
+	})
+
+	It("should forbid values which are not allowed", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"ntp":{"daemon":"systemd-timesyncd"},"sysctl":{"profiles":["network-heavy","hardened"]},"containerd":{"configVersion":null}}`), config)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("ntp.daemon")})),
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("sysctl.profiles[1]")})),
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("containerd.configVersion")})),
+		))
+	})
+
+	It("should forbid to set the policy and type overrides", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"policy":{"lockedFields":[]},"typeOverrides":[]}`), nil)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("policy")})),
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("typeOverrides")})),
+		))
+	})
+
+	It("should apply the policy to the settings of worker pool overrides", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"workerPoolOverrides":[{"pools":["ci"],"config":{"enableDocker":true}},{"pools":["db"],"config":{"ntp":{"daemon":"chrony"},"sysctl":{"settings":{"kernel.panic":"10"}},"containerd":{"configVersion":3}}}]}`), config)
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("workerPoolOverrides[0].config.enableDocker")})),
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("workerPoolOverrides[1].config.sysctl.settings[kernel.panic]")})),
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeNotSupported), "Field": Equal("workerPoolOverrides[1].config.containerd.configVersion")})),
+		))
+	})
+
+	It("should forbid worker pool overrides if they are locked", func() {
+		errs := ValidateProviderConfigPolicy([]byte(`{"workerPoolOverrides":[{"pools":["ci"],"config":{"enableDocker":true}}]}`), &coreosconfig.ExtensionConfig{Policy: &coreosconfig.PolicyConfig{
+			LockedFields: []string{"workerPoolOverrides"},
+		}})
+		Expect(errs).To(ConsistOf(
+			PointTo(MatchFields(IgnoreExtras, Fields{"Type": Equal(field.ErrorTypeForbidden), "Field": Equal("workerPoolOverrides")})),
+		))
+	})
+})
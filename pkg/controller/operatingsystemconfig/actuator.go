@@ -12,32 +12,49 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	ignv3_3 "github.com/coreos/ignition/v2/config/v3_3"
 	igntypes "github.com/coreos/ignition/v2/config/v3_3/types"
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
-	runtimeutils "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
-	configv1alpha1 "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/install"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/validation"
 )
 
-//go:embed templates/configure-cgroupsv2.sh.tpl
-var cgroupsv2TemplateContent string
-
 //go:embed templates/ntp-config.conf.tpl
 var ntpConfigTemplateContent string
 
+//go:embed templates/timesyncd.conf.tpl
+var timesyncdConfigTemplateContent string
+
+//go:embed templates/chrony.conf.tpl
+var chronyConfigTemplateContent string
+
 //go:embed templates/11-exec_config.conf
 var customContainerdServiceOverride string
 
@@ -47,70 +64,227 @@ ExecStart=/bin/true
 Restart=no
 `
 
+// timesyncdConfigPath is the drop-in for the systemd-timesyncd configuration, which takes precedence over the
+// configuration file of the image.
+var timesyncdConfigPath = filepath.Join(string(filepath.Separator), "etc", "systemd", "timesyncd.conf.d", "10-os-coreos.conf")
+
+// chronyConfigPath is the configuration file chronyd reads on Flatcar.
+var chronyConfigPath = filepath.Join(string(filepath.Separator), "etc", "chrony", "chrony.conf")
+
+// chronyNTSTrustedCertsPath contains the CA certificates chronyd uses to verify the NTS-KE servers.
+var chronyNTSTrustedCertsPath = filepath.Join(string(filepath.Separator), "etc", "chrony", "nts-trusted-certs.pem")
+
+// ntpdKeysPath is the keys file of ntpd, which is referenced in the ntp-config.conf.tpl template file.
+var ntpdKeysPath = filepath.Join(string(filepath.Separator), "etc", "ntp.keys")
+
 var ntpConfigTemplate *template.Template
-var decoder runtime.Decoder
+var timesyncdConfigTemplate *template.Template
+var chronyConfigTemplate *template.Template
+var (
+	configScheme *runtime.Scheme
+	decoder      runtime.Decoder
+)
 
 type actuator struct {
 	client          client.Client
+	recorder        events.EventRecorder
 	extensionConfig Config
+	// lock guards the extension config, which is swapped when the config file changes.
+	lock sync.RWMutex
+	// appliedProfiles contains the name of the profile applied to an operating system config by its key. It is only
+	// kept in memory, so that the profile is reported again after a restart of the extension.
+	appliedProfiles sync.Map
 }
 
 // Config contains configuration for the extension service.
 type Config struct {
 	// Embed the entire Extension config here for direct access in the controller.
-	*configv1alpha1.ExtensionConfig
+	*coreosconfig.ExtensionConfig
+	// LenientDecoding ignores unknown and duplicate fields in the provider config of shoots instead of rejecting it.
+	LenientDecoding bool
+	// Types are the types of operating system configs the controller handles. Defaults to DefaultTypes.
+	Types []string
+	// ConfigFile is the path of the file the extension config was read from. If set, the file is watched and changed
+	// configs are swapped into the running actuator.
+	ConfigFile string
+	// LoadExtensionConfig decodes and validates the content of the config file.
+	LoadExtensionConfig func([]byte) (*coreosconfig.ExtensionConfig, error)
+	// ValidateShoots serves a webhook at ShootValidatorPath, which validates the provider config of the machine images of
+	// shoots.
+	ValidateShoots bool
 }
 
 // NewActuator creates a new Actuator that updates the status of the handled OperatingSystemConfigs.
 func NewActuator(mgr manager.Manager, extensionConfig Config) operatingsystemconfig.Actuator {
+	return newActuator(mgr, extensionConfig)
+}
+
+func newActuator(mgr manager.Manager, extensionConfig Config) *actuator {
 	return &actuator{
 		client:          mgr.GetClient(),
+		recorder:        mgr.GetEventRecorder(operatingsystemconfig.ControllerName),
 		extensionConfig: extensionConfig,
 	}
 }
 
+// getExtensionConfig returns the current extension config. It must not be modified, since it is replaced as a whole.
+func (a *actuator) getExtensionConfig() *coreosconfig.ExtensionConfig {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.extensionConfig.ExtensionConfig
+}
+
+// swapExtensionConfig replaces the extension config and returns the previous one.
+func (a *actuator) swapExtensionConfig(config *coreosconfig.ExtensionConfig) *coreosconfig.ExtensionConfig {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	old := a.extensionConfig.ExtensionConfig
+	a.extensionConfig.ExtensionConfig = config
+	return old
+}
+
 func init() {
 	var err error
-	scheme := runtime.NewScheme()
-	runtimeutils.Must(configv1alpha1.AddToScheme(scheme))
-	decoder = serializer.NewCodecFactory(scheme).UniversalDecoder()
+	configScheme = runtime.NewScheme()
+	install.Install(configScheme)
+	decoder = serializer.NewCodecFactory(configScheme, serializer.EnableStrict).UniversalDeserializer()
 	ntpConfigTemplate, err = template.New("ntp-config").Funcs(sprig.TxtFuncMap()).Parse(ntpConfigTemplateContent)
 	if err != nil {
 		panic(fmt.Errorf("failed to parse NTP config template: %w", err))
 	}
+	timesyncdConfigTemplate, err = template.New("timesyncd-config").Funcs(sprig.TxtFuncMap()).Parse(timesyncdConfigTemplateContent)
+	if err != nil {
+		panic(fmt.Errorf("failed to parse timesyncd config template: %w", err))
+	}
+	chronyConfigTemplate, err = template.New("chrony-config").Funcs(sprig.TxtFuncMap()).Parse(chronyConfigTemplateContent)
+	if err != nil {
+		panic(fmt.Errorf("failed to parse chrony config template: %w", err))
+	}
 }
 
-func (a *actuator) GetAndMergeProviderConfiguration(osc *extensionsv1alpha1.OperatingSystemConfig) (*configv1alpha1.ExtensionConfig, error) {
-	shootExtensionConfig := &configv1alpha1.ExtensionConfig{}
-	if _, _, err := decoder.Decode(osc.Spec.ProviderConfig.Raw, nil, shootExtensionConfig); err != nil {
-		return nil, fmt.Errorf("failed to decode provider config: %+v", err)
-	}
+// GetAndMergeProviderConfiguration returns the extension config for the given operating system config. The overrides
+// for its type, the selected profile, the provider config of the shoot and the overrides for its worker pool are merged
+// into the extension config in this order.
+func (a *actuator) GetAndMergeProviderConfiguration(log logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) (*coreosconfig.ExtensionConfig, error) {
+	return a.mergeConfiguration(log, a.getExtensionConfig(), osc)
+}
 
-	config := a.extensionConfig.DeepCopy()
-	if shootExtensionConfig.NTP != nil {
-		config.NTP = shootExtensionConfig.NTP
+// mergeConfiguration merges the configuration for the given operating system config into the given extension config.
+func (a *actuator) mergeConfiguration(log logr.Logger, extensionConfig *coreosconfig.ExtensionConfig, osc *extensionsv1alpha1.OperatingSystemConfig) (*coreosconfig.ExtensionConfig, error) {
+	config, err := mergeTypeOverrides(extensionConfig, osc.Spec.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge overrides for type %q: %w", osc.Spec.Type, err)
+	}
+	if osc.Spec.ProviderConfig != nil {
+		if config, err = a.mergeProviderConfig(log, config, osc.Spec.ProviderConfig.Raw); err != nil {
+			return nil, err
+		}
+	} else if config, err = mergeProfile(config, config.Profile); err != nil {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("failed to merge profile: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
+	}
+	if poolName, ok := osc.Labels[v1beta1constants.LabelWorkerPool]; ok {
+		if config, err = mergeWorkerPoolOverrides(config, poolName); err != nil {
+			return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("failed to merge overrides for worker pool %q: %w", poolName, err), gardencorev1beta1.ErrorConfigurationProblem)
+		}
 	}
 
-	if shootExtensionConfig.EnableDocker != nil {
-		config.EnableDocker = shootExtensionConfig.EnableDocker
+	// If nothing was merged, the extension config is already defaulted and validated.
+	if config != extensionConfig {
+		if err := defaultExtensionConfig(config); err != nil {
+			return nil, fmt.Errorf("failed to default merged config: %w", err)
+		}
+		// The overrides and the provider config are valid on their own, but might not be in combination with the
+		// extension config.
+		if errs := validation.ValidateExtensionConfig(config); len(errs) > 0 {
+			return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid configuration after merging the provider config: %w", errs.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
+		}
+	}
+	containerdConfig := ptr.Deref(config.Containerd, coreosconfig.ContainerdConfig{})
+	if errs := validation.ValidateContainerdRegistryAuth(&containerdConfig, ptr.Deref(containerdConfig.ConfigVersion, defaultContainerdConfigVersion), field.NewPath("containerd")); len(errs) > 0 {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid containerd configuration: %w", errs.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
 	}
 
 	return config, nil
 }
 
-func (a *actuator) Reconcile(ctx context.Context, _ logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) ([]byte, []extensionsv1alpha1.Unit, []extensionsv1alpha1.File, *extensionsv1alpha1.InPlaceUpdatesStatus, error) {
-	var config *configv1alpha1.ExtensionConfig
-	var err error
+// mergeProviderConfig validates the given raw provider config of a shoot and merges it into the given config.
+func (a *actuator) mergeProviderConfig(log logr.Logger, config *coreosconfig.ExtensionConfig, providerConfig []byte) (*coreosconfig.ExtensionConfig, error) {
+	// The strict decoder reports unknown and duplicate fields. Configs without apiVersion and kind are decoded as
+	// v1alpha1, which was the only version before.
+	_, gvk, err := decoder.Decode(providerConfig, ptr.To(v1alpha1.SchemeGroupVersion.WithKind("ExtensionConfig")), nil)
+	if err != nil {
+		strictErrs := validation.StrictDecodingErrors(err)
+		switch {
+		case strictErrs == nil:
+			return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("failed to decode provider config: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
+		case a.extensionConfig.LenientDecoding:
+			log.Info("Ignoring unknown or duplicate fields in provider config", "errors", strictErrs.ToAggregate().Error())
+		default:
+			return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid provider config: %w", strictErrs.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
+		}
+	}
+	// The shoot config is validated on its own without defaults and explicit nulls, which only clear fields when the raw
+	// provider config is merged into the extension config.
+	rawWithoutNulls, err := withoutNulls(providerConfig)
+	if err != nil {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("failed to decode provider config: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
+	}
+	obj, _, err := decoder.Decode(rawWithoutNulls, gvk, nil)
+	if err != nil && !runtime.IsStrictDecodingError(err) {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("failed to decode provider config: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
+	}
+	shootExtensionConfig := &coreosconfig.ExtensionConfig{}
+	if err := configScheme.Convert(obj, shootExtensionConfig, nil); err != nil {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("failed to convert provider config: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
+	}
+	errs := validation.ValidateExtensionConfig(shootExtensionConfig)
+	if errs = append(errs, validation.ValidateProviderConfigSecretRefs(shootExtensionConfig)...); len(errs) > 0 {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid provider config: %w", errs.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
+	}
+	if errs := validation.ValidateProviderConfigPolicy(providerConfig, config); len(errs) > 0 {
+		return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("provider config violates the policy of the extension config: %w", errs.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
+	}
 
-	// Check if the shoot provider configuration is provided. If yes, merge it with the default configuration from the extension.
-	if osc.Spec.ProviderConfig != nil {
-		config, err = a.GetAndMergeProviderConfiguration(osc)
+	merged, err := mergeExtensionConfig(config, shootExtensionConfig, providerConfig, *gvk)
+	if err != nil {
+		return nil, v1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorConfigurationProblem)
+	}
+
+	// The profile is selected in the provider config, or else in the extension config. Its settings are merged before the
+	// provider config, so that shoots can override individual fields on top.
+	if merged.Profile != "" {
+		withProfile, err := mergeProfile(config, merged.Profile)
 		if err != nil {
-			return nil, nil, nil, nil, err
+			return nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid provider config: %w", err), gardencorev1beta1.ErrorConfigurationProblem)
+		}
+		if merged, err = mergeExtensionConfig(withProfile, shootExtensionConfig, providerConfig, *gvk); err != nil {
+			return nil, v1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorConfigurationProblem)
 		}
-	} else {
-		// If no shoot provider configuration is provided, use the default configuration from the extension.
-		config = a.extensionConfig.ExtensionConfig
+	}
+
+	return merged, nil
+}
+
+// defaultExtensionConfig applies the defaults of the preferred API version to the given internal config.
+func defaultExtensionConfig(config *coreosconfig.ExtensionConfig) error {
+	versioned := &v1beta1.ExtensionConfig{}
+	if err := configScheme.Convert(config, versioned, nil); err != nil {
+		return err
+	}
+	configScheme.Default(versioned)
+	return configScheme.Convert(versioned, config, nil)
+}
+
+func (a *actuator) Reconcile(ctx context.Context, log logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) ([]byte, []extensionsv1alpha1.Unit, []extensionsv1alpha1.File, *extensionsv1alpha1.InPlaceUpdatesStatus, error) {
+	config, err := a.GetAndMergeProviderConfiguration(log, osc)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if config.Profile != "" {
+		log.Info("Applying configuration profile", "profile", config.Profile)
+	}
+	if a.profileChanged(osc, config.Profile) && config.Profile != "" {
+		a.recorder.Eventf(osc, nil, corev1.EventTypeNormal, "ConfigurationProfileApplied", "Reconcile", "Applied configuration profile %q", config.Profile)
 	}
 
 	switch purpose := osc.Spec.Purpose; purpose {
@@ -119,7 +293,7 @@ func (a *actuator) Reconcile(ctx context.Context, _ logr.Logger, osc *extensions
 		return []byte(userData), nil, nil, nil, err
 
 	case extensionsv1alpha1.OperatingSystemConfigPurposeReconcile:
-		extensionUnits, extensionFiles, err := a.handleReconcileOSC(config, osc)
+		extensionUnits, extensionFiles, err := a.handleReconcileOSC(ctx, config, osc)
 		return nil, extensionUnits, extensionFiles, nil, err
 
 	default:
@@ -127,7 +301,15 @@ func (a *actuator) Reconcile(ctx context.Context, _ logr.Logger, osc *extensions
 	}
 }
 
-func (a *actuator) Delete(_ context.Context, _ logr.Logger, _ *extensionsv1alpha1.OperatingSystemConfig) error {
+// profileChanged records the given profile as applied to the given operating system config and returns whether it
+// differs from the one of the previous reconciliation, so that events are only emitted when the profile changes.
+func (a *actuator) profileChanged(osc *extensionsv1alpha1.OperatingSystemConfig, profile string) bool {
+	previous, loaded := a.appliedProfiles.Swap(client.ObjectKeyFromObject(osc), profile)
+	return !loaded || previous.(string) != profile
+}
+
+func (a *actuator) Delete(_ context.Context, _ logr.Logger, osc *extensionsv1alpha1.OperatingSystemConfig) error {
+	a.appliedProfiles.Delete(client.ObjectKeyFromObject(osc))
 	return nil
 }
 
@@ -143,28 +325,74 @@ func (a *actuator) Restore(ctx context.Context, logger logr.Logger, osc *extensi
 	return a.Reconcile(ctx, logger, osc)
 }
 
-//go:embed templates/containerd/run-command.sh.tpl
-var containerdTemplateContent string
-
 //go:embed templates/containerd-setup.service
 var containerdSetupUnitContent string
 
-func (a *actuator) handleProvisionOSC(ctx context.Context, config *configv1alpha1.ExtensionConfig, osc *extensionsv1alpha1.OperatingSystemConfig) (string, error) {
+func (a *actuator) handleProvisionOSC(ctx context.Context, config *coreosconfig.ExtensionConfig, osc *extensionsv1alpha1.OperatingSystemConfig) (string, error) {
 	cfg := igntypes.Config{
 		Ignition: igntypes.Ignition{
 			Version: igntypes.MaxVersion.String(),
 		},
 	}
 
-	// Write the containerd setup script. It initialises the containerd config and
-	// patches it for cgroups v2 if necessary. A systemd oneshot unit runs it once
-	// before containerd starts.
+	// Write the containerd configuration, so that it does not depend on the defaults
+	// of the containerd version shipped with the image.
+	cgroupDriver, err := a.getCgroupDriver(ctx, osc)
+	if err != nil {
+		return "", err
+	}
+	containerdConfig, err := generateContainerdConfig(config, osc, cgroupDriver)
+	if err != nil {
+		return "", fmt.Errorf("error generating containerd config: %w", err)
+	}
+	cfg.Storage.Files = append(cfg.Storage.Files, newIgnitionFile(containerdConfigPath, containerdConfig, ptr.To(0o644)))
+
+	// Write the registry configuration of the OSC, so that registry mirrors are used from the first boot.
+	containerdHostsFiles, err := generateContainerdHostsFiles(osc)
+	if err != nil {
+		return "", fmt.Errorf("error generating containerd registry config: %w", err)
+	}
+	for _, file := range containerdHostsFiles {
+		cfg.Storage.Files = append(cfg.Storage.Files, newIgnitionFile(file.Path, file.Content.Inline.Data, ptr.To(0o644)))
+	}
+
+	// Write the registry credentials, so that images of private registries can be pulled before the kubelet starts.
+	containerdRegistryAuthConfig, err := generateContainerdRegistryAuthConfig(ctx, a.client, osc.Namespace, config)
+	if err != nil {
+		return "", fmt.Errorf("error generating containerd registry auth config: %w", err)
+	}
+	if containerdRegistryAuthConfig != "" {
+		cfg.Storage.Files = append(cfg.Storage.Files, newIgnitionFile(containerdRegistryAuthConfigPath, containerdRegistryAuthConfig, ptr.To(0o600)))
+	}
+
+	// The customizations of the machine image version decide which workarounds are needed.
+	customizations, err := a.imageCustomizations(ctx, config, osc)
+	if err != nil {
+		return "", err
+	}
+
+	// Write the containerd setup script. It only initialises the containerd config
+	// as a fallback if it is missing, and applies image specific workarounds. A
+	// systemd oneshot unit runs it once before containerd starts.
+	containerdSetupScript, err := generateContainerdSetupScript(customizations)
+	if err != nil {
+		return "", fmt.Errorf("error generating containerd setup script: %w", err)
+	}
 	cfg.Storage.Files = append(cfg.Storage.Files, newIgnitionFile(
 		"/opt/bin/containerd-setup.sh",
-		containerdTemplateContent,
+		containerdSetupScript,
 		ptr.To(0o755),
 	))
 
+	// Write the kernel parameters so that they are applied by systemd-sysctl already on the first boot.
+	sysctlConfig, err := generateSysctlConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("error generating sysctl config: %w", err)
+	}
+	if sysctlConfig != "" {
+		cfg.Storage.Files = append(cfg.Storage.Files, newIgnitionFile(sysctlConfigPath, sysctlConfig, ptr.To(0o644)))
+	}
+
 	// Convert files from the OSC spec.
 	for _, file := range osc.Spec.Files {
 		source, err := fileContentToDataURI(ctx, a.client, osc.Namespace, file)
@@ -219,12 +447,11 @@ func (a *actuator) handleProvisionOSC(ctx context.Context, config *configv1alpha
 	// vendor "wants" symlinks under /usr/lib/systemd/system, which is read-only
 	// and pulls the units in on every boot regardless of their enablement state.
 	// Masking via /etc (which takes precedence over /usr) is reboot-safe.
-	for _, unitToMask := range []string{
-		"update-engine.service",
-		"locksmithd.service",
-		"systemd-sysupdate.timer",
-		"systemd-sysupdate-reboot.timer",
-	} {
+	unitsToMask := []string{"update-engine.service", "locksmithd.service"}
+	if enabled, ok := customizations[coreosconfig.CustomizationMaskSysupdate]; !ok || enabled {
+		unitsToMask = append(unitsToMask, "systemd-sysupdate.timer", "systemd-sysupdate-reboot.timer")
+	}
+	for _, unitToMask := range unitsToMask {
 		cfg.Storage.Links = append(cfg.Storage.Links, igntypes.Link{
 			Node: igntypes.Node{
 				Path:      "/etc/systemd/system/" + unitToMask,
@@ -294,6 +521,27 @@ func (a *actuator) handleProvisionOSC(ctx context.Context, config *configv1alpha
 		cfg.Systemd.Units = append(cfg.Systemd.Units, ignUnit)
 	}
 
+	// Delay the kubelet until the clock is synchronized already on the first boot, since the kubelet fails the TLS
+	// bootstrap with a wrong clock.
+	if waitForSyncUnits := generateWaitForSyncUnits(config); len(waitForSyncUnits) > 0 {
+		for _, unit := range waitForSyncUnits {
+			ignUnit := igntypes.Unit{Name: unit.Name, Enabled: unit.Enable, Contents: unit.Content}
+			for _, dropin := range unit.DropIns {
+				ignUnit.Dropins = append(ignUnit.Dropins, igntypes.Dropin{Name: dropin.Name, Contents: ptr.To(dropin.Content)})
+			}
+			cfg.Systemd.Units = append(cfg.Systemd.Units, ignUnit)
+		}
+
+		// The kubelet unit is written by gardener-node-agent, but the drop-in can be written before. Ignition does not
+		// accept the same unit twice, so the drop-in is added to the kubelet unit of the OSC if there is one.
+		kubeletDropIn := igntypes.Dropin{Name: kubeletWaitForSyncDropInName, Contents: ptr.To(kubeletWaitForSyncDropIn)}
+		if i := slices.IndexFunc(cfg.Systemd.Units, func(unit igntypes.Unit) bool { return unit.Name == "kubelet.service" }); i >= 0 {
+			cfg.Systemd.Units[i].Dropins = append(cfg.Systemd.Units[i].Dropins, kubeletDropIn)
+		} else {
+			cfg.Systemd.Units = append(cfg.Systemd.Units, igntypes.Unit{Name: "kubelet.service", Dropins: []igntypes.Dropin{kubeletDropIn}})
+		}
+	}
+
 	data, err := json.Marshal(cfg)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal ignition config: %w", err)
@@ -360,7 +608,7 @@ func fileContentToDataURI(ctx context.Context, cl client.Client, namespace strin
 	return "", fmt.Errorf("file %q has neither inline nor secret content", file.Path)
 }
 
-func (a *actuator) generateNTPConfig(config *configv1alpha1.ExtensionConfig) (string, error) {
+func (a *actuator) generateNTPConfig(config *coreosconfig.ExtensionConfig) (string, error) {
 	templateData := config.NTP.NTPD
 	var templateOutput strings.Builder
 
@@ -372,7 +620,70 @@ func (a *actuator) generateNTPConfig(config *configv1alpha1.ExtensionConfig) (st
 	return templateOutput.String(), nil
 }
 
-func (a *actuator) handleReconcileOSC(config *configv1alpha1.ExtensionConfig, _ *extensionsv1alpha1.OperatingSystemConfig) ([]extensionsv1alpha1.Unit, []extensionsv1alpha1.File, error) {
+func (a *actuator) generateTimesyncdConfig(config *coreosconfig.ExtensionConfig) (string, error) {
+	timesyncdConfig := config.NTP.Timesyncd
+	templateData := map[string]any{
+		"Servers":         timesyncdConfig.Servers,
+		"FallbackServers": timesyncdConfig.FallbackServers,
+	}
+	// systemd-timesyncd expects the poll intervals in seconds.
+	if timesyncdConfig.PollIntervalMin != nil {
+		templateData["PollIntervalMinSec"] = int64(timesyncdConfig.PollIntervalMin.Seconds())
+	}
+	if timesyncdConfig.PollIntervalMax != nil {
+		templateData["PollIntervalMaxSec"] = int64(timesyncdConfig.PollIntervalMax.Seconds())
+	}
+
+	var templateOutput strings.Builder
+	if err := timesyncdConfigTemplate.Execute(&templateOutput, templateData); err != nil {
+		return "", fmt.Errorf("error executing template: %v", err)
+	}
+
+	return templateOutput.String(), nil
+}
+
+func (a *actuator) generateChronyConfig(config *coreosconfig.ExtensionConfig) (string, error) {
+	chronyConfig := config.NTP.Chrony
+	makeStep := ptr.Deref(chronyConfig.MakeStep, coreosconfig.ChronyMakeStep{Threshold: metav1.Duration{Duration: time.Second}, Limit: 3})
+	templateData := map[string]any{
+		"Servers":   chronyConfig.Servers,
+		"Pools":     chronyConfig.Pools,
+		"RefClocks": chronyConfig.RefClocks,
+		// chrony expects the threshold in seconds.
+		"MakeStepThreshold":     strconv.FormatFloat(makeStep.Threshold.Seconds(), 'f', -1, 64),
+		"MakeStepLimit":         makeStep.Limit,
+		"RTCSync":               ptr.Deref(chronyConfig.RTCSync, true),
+		"Allow":                 chronyConfig.Allow,
+		"NTS":                   slices.ContainsFunc(slices.Concat(chronyConfig.Servers, chronyConfig.Pools), func(source coreosconfig.ChronySource) bool { return source.NTS }),
+		"RequireAuthentication": ptr.Deref(config.NTP.RequireAuthentication, false),
+	}
+	if chronyConfig.NTSTrustedCertificates != nil {
+		templateData["NTSTrustedCertsPath"] = chronyNTSTrustedCertsPath
+	}
+
+	var templateOutput strings.Builder
+	if err := chronyConfigTemplate.Execute(&templateOutput, templateData); err != nil {
+		return "", fmt.Errorf("error executing template: %v", err)
+	}
+
+	return templateOutput.String(), nil
+}
+
+// generateNTPDKeys reads the symmetric key of the ntpd authentication from the referenced Secret in the given namespace
+// and renders it into the format of the ntpd keys file. Errors never contain the key itself.
+func (a *actuator) generateNTPDKeys(ctx context.Context, namespace string, authentication *coreosconfig.NTPDAuthentication) (string, error) {
+	secret := &corev1.Secret{}
+	if err := a.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: authentication.SecretRef.Name}, secret); err != nil {
+		return "", fmt.Errorf("failed to get secret %q: %w", authentication.SecretRef.Name, err)
+	}
+	key := string(secret.Data["key"])
+	if key == "" || strings.ContainsAny(key, " \t\r\n#") {
+		return "", fmt.Errorf("key %q of secret %q must contain a single key without whitespace", "key", authentication.SecretRef.Name)
+	}
+	return fmt.Sprintf("%d %s %s\n", authentication.KeyID, authentication.Type, key), nil
+}
+
+func (a *actuator) handleReconcileOSC(ctx context.Context, config *coreosconfig.ExtensionConfig, osc *extensionsv1alpha1.OperatingSystemConfig) ([]extensionsv1alpha1.Unit, []extensionsv1alpha1.File, error) {
 	var (
 		extensionUnits []extensionsv1alpha1.Unit
 		extensionFiles []extensionsv1alpha1.File
@@ -397,11 +708,52 @@ func (a *actuator) handleReconcileOSC(config *configv1alpha1.ExtensionConfig, _
 	}
 
 	if ptr.Deref(config.NTP.Enabled, true) {
-		if extensionUnits, extensionFiles, err = a.configureNTPDaemon(config, extensionUnits, extensionFiles); err != nil {
+		cluster, err := extensionscontroller.GetCluster(ctx, a.client, osc.Namespace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting cluster: %w", err)
+		}
+		config = withProviderTimeSources(config, providerType(cluster))
+		if errs := validation.ValidateNTPDaemonConfigured(config.NTP, field.NewPath("ntp")); len(errs) > 0 {
+			return nil, nil, v1beta1helper.NewErrorWithCodes(fmt.Errorf("invalid time synchronization config: %w", errs.ToAggregate()), gardencorev1beta1.ErrorConfigurationProblem)
+		}
+		if extensionUnits, extensionFiles, err = a.configureNTPDaemon(ctx, config, osc.Namespace, extensionUnits, extensionFiles); err != nil {
 			return nil, nil, fmt.Errorf("error configuring NTP Daemon: %v", err)
 		}
 	}
 
+	sysctlConfig, err := generateSysctlConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating sysctl config: %w", err)
+	}
+	if sysctlConfig != "" {
+		// systemd-sysctl only applies the kernel parameters at boot, hence it is restarted whenever they change.
+		extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+			Path:        sysctlConfigPath,
+			Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: sysctlConfig}},
+			Permissions: ptr.To[uint32](0644),
+		})
+		extensionUnits = append(extensionUnits, extensionsv1alpha1.Unit{
+			Name:      "systemd-sysctl.service",
+			Command:   ptr.To(extensionsv1alpha1.CommandRestart),
+			FilePaths: []string{sysctlConfigPath},
+		})
+	}
+
+	// containerd only reads the registry credentials on start, hence it is restarted whenever they change.
+	var containerdFilePaths []string
+	containerdRegistryAuthConfig, err := generateContainerdRegistryAuthConfig(ctx, a.client, osc.Namespace, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating containerd registry auth config: %w", err)
+	}
+	if containerdRegistryAuthConfig != "" {
+		extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+			Path:        containerdRegistryAuthConfigPath,
+			Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: containerdRegistryAuthConfig}},
+			Permissions: ptr.To[uint32](0600),
+		})
+		containerdFilePaths = append(containerdFilePaths, containerdRegistryAuthConfigPath)
+	}
+
 	// blacklist sctp kernel module
 	extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
 		Path:        filepath.Join("/", "etc", "modprobe.d", "sctp.conf"),
@@ -409,23 +761,36 @@ func (a *actuator) handleReconcileOSC(config *configv1alpha1.ExtensionConfig, _
 		Permissions: ptr.To[uint32](0644),
 	})
 
-	// add scripts and dropins for kubelet cgroup driver configuration
-	filePathKubeletCGroupDriverScript := filepath.Join("/", "opt", "bin", "kubelet_cgroup_driver.sh")
+	// configure the kubelet cgroup driver via a kubelet configuration drop-in
+	cgroupDriver, err := a.getCgroupDriver(ctx, osc)
+	if err != nil {
+		return nil, nil, err
+	}
+	filePathKubeletCGroupDriverConfig := filepath.Join(kubeletConfigDropInDir, "10-os-coreos-cgroup-driver.conf")
 	extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
-		Path:        filePathKubeletCGroupDriverScript,
-		Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: cgroupsv2TemplateContent}},
-		Permissions: ptr.To[uint32](0755),
+		Path:        filePathKubeletCGroupDriverConfig,
+		Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: generateKubeletCgroupDriverConfig(cgroupDriver)}},
+		Permissions: ptr.To[uint32](0644),
 	})
-	extensionUnits = append(extensionUnits, extensionsv1alpha1.Unit{
+	kubeletConfigDirDropIn, err := generateKubeletConfigDirDropIn(osc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating kubelet drop-in: %w", err)
+	}
+	// The unit drop-in keeps the name of the former drop-in which patched the kubelet config with an ExecStartPre script.
+	// This way, gardener-node-agent replaces it on existing nodes, and the script is removed with the next OSC update.
+	kubeletUnit := extensionsv1alpha1.Unit{
 		Name: "kubelet.service",
 		DropIns: []extensionsv1alpha1.DropIn{{
-			Name: "10-configure-cgroup-driver.conf",
-			Content: `[Service]
-ExecStartPre=` + filePathKubeletCGroupDriverScript + `
-`,
+			Name:    "10-configure-cgroup-driver.conf",
+			Content: kubeletConfigDirDropIn,
 		}},
-		FilePaths: []string{filePathKubeletCGroupDriverScript},
-	})
+		FilePaths: []string{filePathKubeletCGroupDriverConfig},
+	}
+	if waitForSyncUnits := generateWaitForSyncUnits(config); len(waitForSyncUnits) > 0 {
+		extensionUnits = append(extensionUnits, waitForSyncUnits...)
+		kubeletUnit.DropIns = append(kubeletUnit.DropIns, extensionsv1alpha1.DropIn{Name: kubeletWaitForSyncDropInName, Content: kubeletWaitForSyncDropIn})
+	}
+	extensionUnits = append(extensionUnits, kubeletUnit)
 	extensionUnits = append(extensionUnits, extensionsv1alpha1.Unit{
 		Name: "containerd.service",
 		DropIns: []extensionsv1alpha1.DropIn{
@@ -434,24 +799,37 @@ ExecStartPre=` + filePathKubeletCGroupDriverScript + `
 				Content: customContainerdServiceOverride,
 			},
 		},
+		FilePaths: containerdFilePaths,
 	})
 
 	return extensionUnits, extensionFiles, nil
 }
 
-// configureNTPDaemon configures the VM either with systemd-timesyncd or ntpd as the time syncing client
-func (a *actuator) configureNTPDaemon(config *configv1alpha1.ExtensionConfig, extensionUnits []extensionsv1alpha1.Unit, extensionFiles []extensionsv1alpha1.File) ([]extensionsv1alpha1.Unit, []extensionsv1alpha1.File, error) {
+// configureNTPDaemon configures the VM with systemd-timesyncd, ntpd or chrony as the time syncing client. The units of
+// the other daemons are stopped and disabled, and gardener-node-agent removes their config files written before.
+func (a *actuator) configureNTPDaemon(ctx context.Context, config *coreosconfig.ExtensionConfig, namespace string, extensionUnits []extensionsv1alpha1.Unit, extensionFiles []extensionsv1alpha1.File) ([]extensionsv1alpha1.Unit, []extensionsv1alpha1.File, error) {
 	switch config.NTP.Daemon {
-	case configv1alpha1.SystemdTimesyncd:
+	case coreosconfig.SystemdTimesyncd:
+		timesyncdUnit := extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true)}
+		if config.NTP.Timesyncd != nil {
+			templateData, err := a.generateTimesyncdConfig(config)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error generating timesyncd config: %v", err)
+			}
+			extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+				Path:        timesyncdConfigPath,
+				Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: templateData}},
+				Permissions: ptr.To[uint32](0644),
+			})
+			timesyncdUnit.FilePaths = []string{timesyncdConfigPath}
+		}
 		extensionUnits = append(extensionUnits,
-			extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true)},
+			timesyncdUnit,
 			extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+			extensionsv1alpha1.Unit{Name: "chronyd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
 		)
-	case configv1alpha1.NTPD:
-		extensionUnits = append(extensionUnits,
-			extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
-			extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{filepath.Join(string(filepath.Separator), "etc", "ntp.conf")}},
-		)
+	case coreosconfig.NTPD:
+		ntpdUnit := extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{filepath.Join(string(filepath.Separator), "etc", "ntp.conf")}}
 		templateData, err := a.generateNTPConfig(config)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error generating NTP config: %v", err)
@@ -461,6 +839,50 @@ func (a *actuator) configureNTPDaemon(config *configv1alpha1.ExtensionConfig, ex
 			Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: templateData}},
 			Permissions: ptr.To[uint32](0644),
 		})
+		if config.NTP.NTPD != nil && config.NTP.NTPD.Authentication != nil {
+			keys, err := a.generateNTPDKeys(ctx, namespace, config.NTP.NTPD.Authentication)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error generating NTP keys: %v", err)
+			}
+			extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+				Path:        ntpdKeysPath,
+				Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: keys}},
+				Permissions: ptr.To[uint32](0600),
+			})
+			ntpdUnit.FilePaths = append(ntpdUnit.FilePaths, ntpdKeysPath)
+		}
+		extensionUnits = append(extensionUnits,
+			extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+			ntpdUnit,
+			extensionsv1alpha1.Unit{Name: "chronyd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+		)
+	case coreosconfig.Chrony:
+		chronydUnit := extensionsv1alpha1.Unit{Name: "chronyd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true)}
+		if config.NTP.Chrony != nil {
+			templateData, err := a.generateChronyConfig(config)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error generating chrony config: %v", err)
+			}
+			extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+				Path:        chronyConfigPath,
+				Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: templateData}},
+				Permissions: ptr.To[uint32](0644),
+			})
+			chronydUnit.FilePaths = []string{chronyConfigPath}
+			if config.NTP.Chrony.NTSTrustedCertificates != nil {
+				extensionFiles = append(extensionFiles, extensionsv1alpha1.File{
+					Path:        chronyNTSTrustedCertsPath,
+					Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: *config.NTP.Chrony.NTSTrustedCertificates}},
+					Permissions: ptr.To[uint32](0644),
+				})
+				chronydUnit.FilePaths = append(chronydUnit.FilePaths, chronyNTSTrustedCertsPath)
+			}
+		}
+		extensionUnits = append(extensionUnits,
+			extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+			extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+			chronydUnit,
+		)
 	default:
 		return nil, nil, fmt.Errorf("unsupported NTP daemon: %s", config.NTP.Daemon)
 	}
@@ -7,26 +7,38 @@ package operatingsystemconfig
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	stdjson "encoding/json"
 	"path/filepath"
+	"strings"
+	"time"
 
 	igntypes "github.com/coreos/ignition/v2/config/v3_3/types"
 	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
 	"github.com/gardener/gardener/pkg/utils/test"
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
-	runtimeutils "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
-	configv1alpha1 "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/install"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1alpha1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
 )
 
 // ignitionTestConfig mirrors the Ignition v3 JSON structure for test assertions only.
@@ -61,113 +73,42 @@ type ignitionTestConfig struct {
 	} `json:"systemd"`
 }
 
-var _ = Describe("Actuator", func() {
-	var (
-		scheme  = runtime.NewScheme()
-		encoder runtime.Encoder
-	)
-
-	BeforeEach(func() {
-		runtimeutils.Must(configv1alpha1.AddToScheme(scheme))
-		encoder = serializer.NewCodecFactory(scheme).EncoderForVersion(&json.Serializer{}, configv1alpha1.SchemeGroupVersion)
-
-	})
-
-	DescribeTable("GetAndMergeProviderConfiguration", func(extensionConfig, shootConfig, expectedConfig configv1alpha1.ExtensionConfig) {
-		providerConfigBuffer := new(bytes.Buffer)
-		Expect(encoder.Encode(&shootConfig, providerConfigBuffer)).To(Succeed())
-		osc := &extensionsv1alpha1.OperatingSystemConfig{
-			Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
-				DefaultSpec: extensionsv1alpha1.DefaultSpec{
-					ProviderConfig: &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()},
-				},
-			},
-		}
-		a := &actuator{
-			extensionConfig: Config{&extensionConfig},
-		}
-		config, err := a.GetAndMergeProviderConfiguration(osc)
-		Expect(err).NotTo(HaveOccurred())
-		Expect(config).ToNot(BeNil())
-		configv1alpha1.SetObjectDefaults_ExtensionConfig(&expectedConfig)
-		Expect(*config).To(Equal(expectedConfig))
-	},
-		Entry("no shoot config",
-			configv1alpha1.ExtensionConfig{
-				EnableDocker: new(false),
-				NTP: &configv1alpha1.NTPConfig{
-					Enabled: ptr.To(true),
-					Daemon:  configv1alpha1.SystemdTimesyncd,
-				},
-			},
-			configv1alpha1.ExtensionConfig{},
-			configv1alpha1.ExtensionConfig{
-				EnableDocker: new(false),
-				NTP: &configv1alpha1.NTPConfig{
-					Enabled: ptr.To(true),
-					Daemon:  configv1alpha1.SystemdTimesyncd,
-				},
-			}),
-		Entry("overwrite DisableDocker",
-			configv1alpha1.ExtensionConfig{
-				EnableDocker: new(true),
-			},
-			configv1alpha1.ExtensionConfig{
-				EnableDocker: new(false),
-			},
-			configv1alpha1.ExtensionConfig{
-				EnableDocker: new(false),
-			}),
-		Entry("overwrite ntp",
-			configv1alpha1.ExtensionConfig{
-				NTP: &configv1alpha1.NTPConfig{
-					Enabled: ptr.To(true),
-					Daemon:  configv1alpha1.SystemdTimesyncd,
-				}},
-			configv1alpha1.ExtensionConfig{
-				NTP: &configv1alpha1.NTPConfig{
-					Enabled: ptr.To(true),
-					Daemon:  configv1alpha1.NTPD,
-				}},
-			configv1alpha1.ExtensionConfig{
-				NTP: &configv1alpha1.NTPConfig{
-					Enabled: ptr.To(true),
-					Daemon:  configv1alpha1.NTPD,
-				}}),
-	)
-})
-
 var _ = Describe("Actuator", func() {
 	var (
 		ctx        = context.TODO()
 		log        = logr.Discard()
 		fakeClient client.Client
 		mgr        manager.Manager
+		recorder   *events.FakeRecorder
 
 		osc                   *extensionsv1alpha1.OperatingSystemConfig
-		actuator              operatingsystemconfig.Actuator
-		globalExtensionConfig *configv1alpha1.ExtensionConfig
+		oscActuator           operatingsystemconfig.Actuator
+		globalExtensionConfig *coreosconfig.ExtensionConfig
 		scheme                = runtime.NewScheme()
 		encoder               runtime.Encoder
 	)
 
 	BeforeEach(func() {
-		fakeClient = fakeclient.NewClientBuilder().Build()
-		runtimeutils.Must(configv1alpha1.AddToScheme(scheme))
-		encoder = serializer.NewCodecFactory(scheme).EncoderForVersion(&json.Serializer{}, configv1alpha1.SchemeGroupVersion)
-		mgr = test.FakeManager{Client: fakeClient}
+		fakeClient = fakeclient.NewClientBuilder().WithScheme(kubernetes.SeedScheme).Build()
+		install.Install(scheme)
+		encoder = serializer.NewCodecFactory(scheme).EncoderForVersion(&json.Serializer{}, v1alpha1.SchemeGroupVersion)
+		recorder = events.NewFakeRecorder(10)
+		mgr = test.FakeManager{Client: fakeClient, EventRecorder: recorder}
 		extensionConfig := Config{
-			ExtensionConfig: &configv1alpha1.ExtensionConfig{
-				NTP: &configv1alpha1.NTPConfig{
+			ExtensionConfig: &coreosconfig.ExtensionConfig{
+				NTP: &coreosconfig.NTPConfig{
 					Enabled: ptr.To(true),
-					Daemon:  configv1alpha1.SystemdTimesyncd,
+					Daemon:  coreosconfig.SystemdTimesyncd,
 				},
 			},
 		}
 		globalExtensionConfig = extensionConfig.ExtensionConfig
-		actuator = NewActuator(mgr, extensionConfig)
+		oscActuator = NewActuator(mgr, extensionConfig)
+
+		Expect(fakeClient.Create(ctx, newCluster("shoot--foo--bar", "local"))).To(Succeed())
 
 		osc = &extensionsv1alpha1.OperatingSystemConfig{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot--foo--bar"},
 			Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
 				Purpose: extensionsv1alpha1.OperatingSystemConfigPurposeProvision,
 				Units:   []extensionsv1alpha1.Unit{{Name: "some-unit.service", Content: ptr.To("[Unit]\nDescription=Some Unit\n[Install]\nWantedBy=multi-user.target")}},
@@ -176,10 +117,529 @@ var _ = Describe("Actuator", func() {
 		}
 	})
 
+	Describe("#GetAndMergeProviderConfiguration", func() {
+		BeforeEach(func() {
+			// Empty daemons and servers are only omitted in v1beta1, in v1alpha1 they are encoded and merged as set.
+			encoder = serializer.NewCodecFactory(scheme).EncoderForVersion(&json.Serializer{}, v1beta1.SchemeGroupVersion)
+		})
+
+		DescribeTable("should merge the provider config into the extension config", func(extensionConfig, shootConfig, expectedConfig coreosconfig.ExtensionConfig) {
+			providerConfigBuffer := new(bytes.Buffer)
+			Expect(encoder.Encode(&shootConfig, providerConfigBuffer)).To(Succeed())
+			osc := &extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{
+						ProviderConfig: &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()},
+					},
+				},
+			}
+			a := &actuator{
+				extensionConfig: Config{ExtensionConfig: &extensionConfig},
+			}
+			config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config).ToNot(BeNil())
+			Expect(defaultExtensionConfig(&expectedConfig)).To(Succeed())
+			Expect(*config).To(Equal(expectedConfig))
+		},
+			Entry("no shoot config",
+				coreosconfig.ExtensionConfig{
+					EnableDocker: new(false),
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.SystemdTimesyncd,
+					},
+				},
+				coreosconfig.ExtensionConfig{},
+				coreosconfig.ExtensionConfig{
+					EnableDocker: new(false),
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.SystemdTimesyncd,
+					},
+				}),
+			Entry("overwrite DisableDocker",
+				coreosconfig.ExtensionConfig{
+					EnableDocker: new(true),
+				},
+				coreosconfig.ExtensionConfig{
+					EnableDocker: new(false),
+				},
+				coreosconfig.ExtensionConfig{
+					EnableDocker: new(false),
+				}),
+			Entry("overwrite ntp",
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.SystemdTimesyncd,
+					}},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+					}},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+					}}),
+			Entry("merge containerd",
+				coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+					}},
+				coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						RegistryAuth: []coreosconfig.RegistryAuth{{
+							Registry:  "registry.example.com",
+							SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"},
+						}},
+					}},
+				coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+						RegistryAuth: []coreosconfig.RegistryAuth{{
+							Registry:  "registry.example.com",
+							SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"},
+						}},
+					}}),
+			Entry("keep the settings of the extension config if the shoot only enables docker",
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+					},
+					Sysctl:     &coreosconfig.SysctlConfig{Profiles: []coreosconfig.SysctlProfile{"hardened"}},
+					Containerd: &coreosconfig.ContainerdConfig{SandboxImage: ptr.To("registry.example.com/pause:3.10")},
+				},
+				coreosconfig.ExtensionConfig{
+					EnableDocker: ptr.To(true),
+				},
+				coreosconfig.ExtensionConfig{
+					EnableDocker: ptr.To(true),
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+					},
+					Sysctl:     &coreosconfig.SysctlConfig{Profiles: []coreosconfig.SysctlProfile{"hardened"}},
+					Containerd: &coreosconfig.ContainerdConfig{SandboxImage: ptr.To("registry.example.com/pause:3.10")},
+				}),
+			Entry("merge ntp field by field",
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						WaitForSync: &coreosconfig.NTPWaitForSync{Enabled: true},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled:     ptr.To(true),
+						Daemon:      coreosconfig.NTPD,
+						NTPD:        &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+						WaitForSync: &coreosconfig.NTPWaitForSync{Enabled: true},
+					},
+				}),
+			Entry("drop the daemon sections of the extension config if the shoot switches the daemon",
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Daemon: coreosconfig.Chrony,
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.Chrony,
+					},
+				}),
+			Entry("merge sysctl settings",
+				coreosconfig.ExtensionConfig{
+					Sysctl: &coreosconfig.SysctlConfig{
+						Profiles: []coreosconfig.SysctlProfile{"hardened"},
+						Settings: map[string]string{"vm.max_map_count": "262144", "fs.inotify.max_user_watches": "524288"},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					Sysctl: &coreosconfig.SysctlConfig{
+						Settings: map[string]string{"vm.max_map_count": "524288"},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					Sysctl: &coreosconfig.SysctlConfig{
+						Profiles: []coreosconfig.SysctlProfile{"hardened"},
+						Settings: map[string]string{"vm.max_map_count": "524288", "fs.inotify.max_user_watches": "524288"},
+					},
+				}),
+			Entry("keep the servers of the extension config if the shoot only disables ntp",
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(false),
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(false),
+						Daemon:  coreosconfig.NTPD,
+						NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+					},
+				}),
+			Entry("merge the daemon sections field by field and replace lists",
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD: &coreosconfig.NTPDConfig{
+							Servers:   []string{"ntp.example.com"},
+							Restrict:  []string{"default ignore"},
+							DriftFile: ptr.To("/var/lib/ntp/drift"),
+						},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						NTPD: &coreosconfig.NTPDConfig{Servers: []string{"foo.bar", "bar.foo"}},
+					},
+				},
+				coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD: &coreosconfig.NTPDConfig{
+							Servers:   []string{"foo.bar", "bar.foo"},
+							Restrict:  []string{"default ignore"},
+							DriftFile: ptr.To("/var/lib/ntp/drift"),
+						},
+					},
+				}),
+			Entry("merge the registry credentials by registry",
+				coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+						RegistryAuth: []coreosconfig.RegistryAuth{
+							{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-foo"}},
+							{Registry: "mirror.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-mirror"}},
+						},
+					}},
+				coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						RegistryAuth: []coreosconfig.RegistryAuth{
+							{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-bar"}},
+							{Registry: "registry.example.org", SecretRef: corev1.LocalObjectReference{Name: "ref-org"}},
+						},
+					}},
+				coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+						RegistryAuth: []coreosconfig.RegistryAuth{
+							{Registry: "registry.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-bar"}},
+							{Registry: "registry.example.org", SecretRef: corev1.LocalObjectReference{Name: "ref-org"}},
+							{Registry: "mirror.example.com", SecretRef: corev1.LocalObjectReference{Name: "ref-mirror"}},
+						},
+					}}),
+		)
+
+		It("should keep the daemon of the extension config if a v1alpha1 shoot config does not set it", func() {
+			osc := &extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{
+						ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1alpha1","kind":"ExtensionConfig","ntp":{"waitForSync":{"enabled":true}}}`)},
+					},
+				},
+			}
+			a := &actuator{extensionConfig: Config{ExtensionConfig: &coreosconfig.ExtensionConfig{NTP: &coreosconfig.NTPConfig{
+				Enabled: ptr.To(true),
+				Daemon:  coreosconfig.NTPD,
+				NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}},
+			}}}}
+
+			config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.NTP.Daemon).To(Equal(coreosconfig.NTPD))
+			Expect(config.NTP.NTPD).To(Equal(&coreosconfig.NTPDConfig{Servers: []string{"ntp.example.com"}}))
+			Expect(config.NTP.WaitForSync).To(Equal(&coreosconfig.NTPWaitForSync{Enabled: true}))
+		})
+
+		DescribeTable("should default the extension config but not the configs of overrides and profiles",
+			func(apiVersion string) {
+				obj, _, err := decoder.Decode([]byte(`{"apiVersion":"`+apiVersion+`","kind":"ExtensionConfig",`+
+					`"typeOverrides":[{"types":["flatcar"],"config":{"ntp":{"waitForSync":{"enabled":true}}}}],`+
+					`"workerPoolOverrides":[{"pools":["pool"],"config":{"ntp":{"enabled":false}}}],`+
+					`"profiles":[{"name":"chrony","config":{"ntp":{"daemon":"chrony"}}}]}`), nil, nil)
+				Expect(err).NotTo(HaveOccurred())
+				configScheme.Default(obj)
+				config := &coreosconfig.ExtensionConfig{}
+				Expect(configScheme.Convert(obj, config, nil)).To(Succeed())
+
+				Expect(config.NTP).To(Equal(&coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.SystemdTimesyncd}))
+				// Defaults in the nested configs would overwrite the settings they are merged into.
+				Expect(config.TypeOverrides[0].Config.NTP).To(Equal(&coreosconfig.NTPConfig{WaitForSync: &coreosconfig.NTPWaitForSync{Enabled: true}}))
+				Expect(config.WorkerPoolOverrides[0].Config.NTP).To(Equal(&coreosconfig.NTPConfig{Enabled: ptr.To(false)}))
+				Expect(config.Profiles[0].Config.NTP).To(Equal(&coreosconfig.NTPConfig{Daemon: coreosconfig.Chrony}))
+			},
+			Entry("v1alpha1", v1alpha1.SchemeGroupVersion.String()),
+			Entry("v1beta1", v1beta1.SchemeGroupVersion.String()),
+		)
+
+		It("should clear fields of the extension config which are null in the shoot config", func() {
+			osc := &extensionsv1alpha1.OperatingSystemConfig{
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{
+						ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","sysctl":{"settings":{"vm.max_map_count":null}},"containerd":{"sandboxImage":null}}`)},
+					},
+				},
+			}
+			a := &actuator{
+				extensionConfig: Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					Sysctl: &coreosconfig.SysctlConfig{
+						Settings: map[string]string{"vm.max_map_count": "262144", "fs.inotify.max_user_watches": "524288"},
+					},
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+						SandboxImage:  ptr.To("registry.example.com/pause:3.10"),
+					},
+				}},
+			}
+			config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Sysctl.Settings).To(Equal(map[string]string{"fs.inotify.max_user_watches": "524288"}))
+			Expect(config.Containerd.ConfigVersion).To(HaveValue(BeEquivalentTo(3)))
+			Expect(config.Containerd.SandboxImage).To(BeNil())
+		})
+
+		Context("type overrides", func() {
+			var a *actuator
+
+			BeforeEach(func() {
+				a = &actuator{
+					extensionConfig: Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+						EnableDocker: ptr.To(false),
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.SystemdTimesyncd,
+						},
+						TypeOverrides: []coreosconfig.TypeOverride{
+							{
+								Types:  []string{"flatcar-lts"},
+								Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{ConfigVersion: ptr.To[int32](2)}},
+							},
+							{
+								Types: []string{"flatcar-alpha", "flatcar-lts"},
+								Config: coreosconfig.ExtensionConfig{
+									NTP:        &coreosconfig.NTPConfig{Daemon: coreosconfig.Chrony, Chrony: &coreosconfig.ChronyConfig{Servers: []coreosconfig.ChronySource{{Address: "ntp.example.com"}}}},
+									Containerd: &coreosconfig.ContainerdConfig{Snapshotter: ptr.To("native")},
+								},
+							},
+						},
+					}},
+				}
+			})
+
+			It("should return the extension config if no override applies", func() {
+				osc := &extensionsv1alpha1.OperatingSystemConfig{Spec: extensionsv1alpha1.OperatingSystemConfigSpec{DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "flatcar"}}}
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config).To(BeIdenticalTo(a.extensionConfig.ExtensionConfig))
+			})
+
+			It("should merge all overrides for the type in the given order", func() {
+				osc := &extensionsv1alpha1.OperatingSystemConfig{Spec: extensionsv1alpha1.OperatingSystemConfigSpec{DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "flatcar-lts"}}}
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.EnableDocker).To(HaveValue(BeFalse()))
+				Expect(config.NTP).To(Equal(&coreosconfig.NTPConfig{
+					Enabled: ptr.To(true),
+					Daemon:  coreosconfig.Chrony,
+					Chrony:  &coreosconfig.ChronyConfig{Servers: []coreosconfig.ChronySource{{Address: "ntp.example.com"}}},
+				}))
+				Expect(config.Containerd).To(Equal(&coreosconfig.ContainerdConfig{ConfigVersion: ptr.To[int32](2), Snapshotter: ptr.To("native")}))
+				Expect(a.extensionConfig.Containerd).To(BeNil())
+			})
+
+			It("should merge the provider config of the shoot after the overrides", func() {
+				osc := &extensionsv1alpha1.OperatingSystemConfig{Spec: extensionsv1alpha1.OperatingSystemConfigSpec{DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type:           "flatcar-alpha",
+					ProviderConfig: &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","enableDocker":true,"containerd":{"snapshotter":"overlayfs"}}`)},
+				}}}
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.EnableDocker).To(HaveValue(BeTrue()))
+				Expect(config.NTP.Daemon).To(Equal(coreosconfig.Chrony))
+				Expect(config.Containerd).To(Equal(&coreosconfig.ContainerdConfig{Snapshotter: ptr.To("overlayfs")}))
+			})
+		})
+
+		Context("worker pool overrides", func() {
+			const providerConfig = `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","containerd":{"snapshotter":"overlayfs"},"workerPoolOverrides":[{"pools":["ci"],"config":{"enableDocker":true}}]}`
+
+			var (
+				a   *actuator
+				osc *extensionsv1alpha1.OperatingSystemConfig
+			)
+
+			BeforeEach(func() {
+				a = &actuator{
+					extensionConfig: Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+						EnableDocker: ptr.To(false),
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.SystemdTimesyncd,
+						},
+					}},
+				}
+				osc = &extensionsv1alpha1.OperatingSystemConfig{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"worker.gardener.cloud/pool": "ci"}},
+					Spec: extensionsv1alpha1.OperatingSystemConfigSpec{DefaultSpec: extensionsv1alpha1.DefaultSpec{
+						Type:           "flatcar",
+						ProviderConfig: &runtime.RawExtension{Raw: []byte(providerConfig)},
+					}},
+				}
+			})
+
+			It("should merge the overrides of the provider config for the worker pool", func() {
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.EnableDocker).To(HaveValue(BeTrue()))
+				Expect(config.Containerd).To(Equal(&coreosconfig.ContainerdConfig{Snapshotter: ptr.To("overlayfs")}))
+			})
+
+			It("should not merge the overrides for other worker pools", func() {
+				osc.Labels["worker.gardener.cloud/pool"] = "default"
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.EnableDocker).To(HaveValue(BeFalse()))
+				Expect(config.Containerd).To(Equal(&coreosconfig.ContainerdConfig{Snapshotter: ptr.To("overlayfs")}))
+			})
+
+			It("should merge the overrides of the extension config after the provider config", func() {
+				osc.Spec.ProviderConfig.Raw = []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","containerd":{"snapshotter":"overlayfs"}}`)
+				a.extensionConfig.WorkerPoolOverrides = []coreosconfig.WorkerPoolOverride{{
+					Pools:  []string{"ci"},
+					Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{Snapshotter: ptr.To("native")}},
+				}}
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Containerd).To(Equal(&coreosconfig.ContainerdConfig{Snapshotter: ptr.To("native")}))
+			})
+
+			It("should keep the overrides of the extension config if the provider config has overrides", func() {
+				a.extensionConfig.WorkerPoolOverrides = []coreosconfig.WorkerPoolOverride{{
+					Pools:  []string{"ci"},
+					Config: coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{Snapshotter: ptr.To("native")}, EnableDocker: ptr.To(false)},
+				}}
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Containerd).To(Equal(&coreosconfig.ContainerdConfig{Snapshotter: ptr.To("native")}))
+				Expect(config.EnableDocker).To(HaveValue(BeTrue()))
+			})
+
+			It("should apply the policy to the overrides of the provider config", func() {
+				a.extensionConfig.Policy = &coreosconfig.PolicyConfig{LockedFields: []string{"enableDocker"}}
+				_, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).To(MatchError(ContainSubstring("workerPoolOverrides[0].config.enableDocker: Forbidden: field is locked by the extension config")))
+			})
+		})
+
+		Context("profiles", func() {
+			var (
+				a   *actuator
+				osc *extensionsv1alpha1.OperatingSystemConfig
+			)
+
+			BeforeEach(func() {
+				a = &actuator{
+					extensionConfig: Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+						EnableDocker: ptr.To(false),
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.SystemdTimesyncd,
+						},
+						Profiles: []coreosconfig.ConfigProfile{
+							{
+								Name:   "legacy-docker",
+								Config: coreosconfig.ExtensionConfig{EnableDocker: ptr.To(true)},
+							},
+							{
+								Name: "performance",
+								Config: coreosconfig.ExtensionConfig{Sysctl: &coreosconfig.SysctlConfig{
+									Settings: map[string]string{"vm.max_map_count": "262144", "net.core.somaxconn": "4096"},
+								}},
+							},
+						},
+					}},
+				}
+				osc = &extensionsv1alpha1.OperatingSystemConfig{Spec: extensionsv1alpha1.OperatingSystemConfigSpec{DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "flatcar"}}}
+			})
+
+			It("should merge the profile selected in the provider config", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","profile":"legacy-docker"}`)}
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Profile).To(Equal("legacy-docker"))
+				Expect(config.EnableDocker).To(HaveValue(BeTrue()))
+				Expect(a.extensionConfig.EnableDocker).To(HaveValue(BeFalse()))
+			})
+
+			It("should merge the provider config on top of the profile", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","profile":"performance","sysctl":{"settings":{"net.core.somaxconn":"1024"}}}`)}
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Profile).To(Equal("performance"))
+				Expect(config.Sysctl.Settings).To(Equal(map[string]string{"vm.max_map_count": "262144", "net.core.somaxconn": "1024"}))
+			})
+
+			It("should merge the profile of the extension config unless the provider config clears it", func() {
+				a.extensionConfig.Profile = "legacy-docker"
+				config, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.EnableDocker).To(HaveValue(BeTrue()))
+
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","profile":null}`)}
+				config, err = a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.Profile).To(BeEmpty())
+				Expect(config.EnableDocker).To(HaveValue(BeFalse()))
+			})
+
+			It("should fail if the selected profile is unknown", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","profile":"hardened"}`)}
+				_, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).To(MatchError(ContainSubstring(`profile: Unsupported value: "hardened": supported values: "legacy-docker", "performance"`)))
+			})
+
+			It("should forbid profiles in the provider config", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","profiles":[{"name":"custom","config":{"enableDocker":true}}]}`)}
+				_, err := a.GetAndMergeProviderConfiguration(logr.Discard(), osc)
+				Expect(err).To(MatchError(ContainSubstring("profiles: Forbidden: may only be set in the extension config")))
+			})
+		})
+	})
+
 	When("purpose is 'provision'", func() {
 		Describe("#Reconcile", func() {
 			It("should return a valid Ignition v3 config JSON", func() {
-				userData, extensionUnits, extensionFiles, inplaceUpdateStatus, err := actuator.Reconcile(ctx, log, osc)
+				userData, extensionUnits, extensionFiles, inplaceUpdateStatus, err := oscActuator.Reconcile(ctx, log, osc)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(extensionUnits).To(BeEmpty())
 				Expect(extensionFiles).To(BeEmpty())
@@ -206,6 +666,31 @@ var _ = Describe("Actuator", func() {
 					}
 				}
 
+				By("writing the containerd config with the systemd cgroup driver")
+				Expect(ign.Storage.Files).To(ContainElement(SatisfyAll(
+					HaveField("Path", "/etc/containerd/config.toml"),
+					HaveField("Contents.Source", "data:;base64,"+base64.StdEncoding.EncodeToString([]byte(`version = 2
+imports = ["/etc/containerd/conf.d/*.toml"]
+
+[plugins]
+  [plugins."io.containerd.grpc.v1.cri"]
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+      snapshotter = "overlayfs"
+      default_runtime_name = "runc"
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+            SystemdCgroup = true
+    [plugins."io.containerd.grpc.v1.cri".registry]
+      config_path = "/etc/containerd/certs.d"
+`))),
+					HaveField("Mode", ptr.To(0o644)),
+				)))
+
+				By("not writing kernel parameters if none are configured")
+				Expect(filePaths).NotTo(ContainElement("/etc/sysctl.d/99-os-coreos.conf"))
+
 				By("including OSC files")
 				Expect(filePaths).To(ContainElement("/some/file"))
 				for _, f := range ign.Storage.Files {
@@ -302,7 +787,7 @@ var _ = Describe("Actuator", func() {
 
 			It("should override global defaults on a shoot by shoot basis and enable docker", func() {
 				// Shoot specific override via providerConfig
-				providerConfigData := configv1alpha1.ExtensionConfig{
+				providerConfigData := coreosconfig.ExtensionConfig{
 					EnableDocker: new(true),
 				}
 				providerConfigBuffer := new(bytes.Buffer)
@@ -312,7 +797,7 @@ var _ = Describe("Actuator", func() {
 					osc.Spec.ProviderConfig = nil
 				})
 				Expect(err).NotTo(HaveOccurred())
-				userData, _, _, _, err := actuator.Reconcile(ctx, log, osc)
+				userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
 				Expect(err).NotTo(HaveOccurred())
 
 				var ign ignitionTestConfig
@@ -338,19 +823,430 @@ var _ = Describe("Actuator", func() {
 		})
 	})
 
+	When("purpose is 'provision'", func() {
+		It("should write the kernel parameters as a file", func() {
+			extensionConfig := Config{
+				ExtensionConfig: &coreosconfig.ExtensionConfig{
+					Sysctl: &coreosconfig.SysctlConfig{
+						Settings: map[string]string{"vm.max_map_count": "262144"},
+					},
+				},
+			}
+			oscActuator = NewActuator(mgr, extensionConfig)
+			userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var ign ignitionTestConfig
+			Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+			Expect(ign.Storage.Files).To(ContainElement(SatisfyAll(
+				HaveField("Path", "/etc/sysctl.d/99-os-coreos.conf"),
+				HaveField("Contents.Source", "data:;base64,"+base64.StdEncoding.EncodeToString([]byte("vm.max_map_count = 262144\n"))),
+				HaveField("Mode", ptr.To(0o644)),
+			)))
+		})
+	})
+
+	When("purpose is 'provision'", func() {
+		It("should delay the kubelet until systemd-time-wait-sync reports a synchronized clock", func() {
+			oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+				NTP: &coreosconfig.NTPConfig{
+					Enabled:     ptr.To(true),
+					Daemon:      coreosconfig.SystemdTimesyncd,
+					WaitForSync: &coreosconfig.NTPWaitForSync{Enabled: true, Timeout: &metav1.Duration{Duration: 5 * time.Minute}},
+				},
+			}})
+			osc.Spec.Units = append(osc.Spec.Units, extensionsv1alpha1.Unit{Name: "kubelet.service", DropIns: []extensionsv1alpha1.DropIn{{Name: "10-foo.conf", Content: "[Service]\n"}}})
+			userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var ign ignitionTestConfig
+			Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+			Expect(ign.Systemd.Units).To(ContainElement(SatisfyAll(
+				HaveField("Name", "systemd-time-wait-sync.service"),
+				HaveField("Enabled", ptr.To(true)),
+				HaveField("Dropins", ConsistOf(SatisfyAll(HaveField("Name", "10-timeout.conf"), HaveField("Contents", ptr.To("[Service]\nTimeoutStartSec=300\n"))))),
+			)))
+			Expect(ign.Systemd.Units).To(ContainElement(SatisfyAll(
+				HaveField("Name", "kubelet.service"),
+				HaveField("Dropins", ConsistOf(
+					HaveField("Name", "10-foo.conf"),
+					SatisfyAll(HaveField("Name", "30-wait-for-time-sync.conf"), HaveField("Contents", ptr.To("[Unit]\nWants=time-sync.target\nAfter=time-sync.target\n"))),
+				)),
+			)))
+		})
+
+		It("should not delay the kubelet by default", func() {
+			userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var ign ignitionTestConfig
+			Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+			Expect(ign.Systemd.Units).NotTo(ContainElement(HaveField("Name", "systemd-time-wait-sync.service")))
+			Expect(ign.Systemd.Units).NotTo(ContainElement(HaveField("Name", "kubelet.service")))
+		})
+	})
+
+	When("purpose is 'provision'", func() {
+		It("should write the containerd config in version 3 with sandbox image and snapshotter", func() {
+			extensionConfig := Config{
+				ExtensionConfig: &coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+						SandboxImage:  ptr.To("registry.k8s.io/pause:3.10"),
+						Snapshotter:   ptr.To("native"),
+					},
+				},
+			}
+			oscActuator = NewActuator(mgr, extensionConfig)
+			userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var ign ignitionTestConfig
+			Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+			Expect(ign.Storage.Files).To(ContainElement(SatisfyAll(
+				HaveField("Path", "/etc/containerd/config.toml"),
+				HaveField("Contents.Source", "data:;base64,"+base64.StdEncoding.EncodeToString([]byte(`version = 3
+imports = ["/etc/containerd/conf.d/*.toml"]
+
+[plugins]
+  [plugins."io.containerd.cri.v1.images"]
+    snapshotter = "native"
+    [plugins."io.containerd.cri.v1.images".pinned_images]
+      sandbox = "registry.k8s.io/pause:3.10"
+    [plugins."io.containerd.cri.v1.images".registry]
+      config_path = "/etc/containerd/certs.d"
+  [plugins."io.containerd.cri.v1.runtime"]
+    [plugins."io.containerd.cri.v1.runtime".containerd]
+      default_runtime_name = "runc"
+      [plugins."io.containerd.cri.v1.runtime".containerd.runtimes]
+        [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+          [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc.options]
+            SystemdCgroup = true
+`))),
+			)))
+		})
+	})
+
+	When("purpose is 'provision'", func() {
+		BeforeEach(func() {
+			osc.Spec.CRIConfig = &extensionsv1alpha1.CRIConfig{
+				Name: extensionsv1alpha1.CRINameContainerD,
+				Containerd: &extensionsv1alpha1.ContainerdConfig{
+					SandboxImage: "europe-docker.pkg.dev/gardener-project/releases/pause:3.10",
+					Registries: []extensionsv1alpha1.RegistryConfig{
+						{
+							Upstream: "docker.io",
+							Server:   ptr.To("https://registry-1.docker.io"),
+							Hosts: []extensionsv1alpha1.RegistryHost{
+								{URL: "https://mirror.example.com"},
+								{URL: "https://private.example.com", Capabilities: []extensionsv1alpha1.RegistryCapability{extensionsv1alpha1.PullCapability}, CACerts: []string{"/etc/ssl/private-ca.pem"}, OverridePath: ptr.To(true)},
+							},
+						},
+						{
+							Upstream:       "in-cluster.local",
+							Hosts:          []extensionsv1alpha1.RegistryHost{{URL: "http://10.0.0.1:5000"}},
+							ReadinessProbe: ptr.To(true),
+						},
+					},
+					Plugins: []extensionsv1alpha1.PluginConfig{
+						{
+							Path:   []string{"io.containerd.grpc.v1.cri", "containerd", "runtimes", "runc", "options"},
+							Values: &apiextensionsv1.JSON{Raw: []byte(`{"BinaryName":"/usr/bin/crun"}`)},
+						},
+						{
+							Op:   ptr.To(extensionsv1alpha1.RemovePluginPathOperation),
+							Path: []string{"io.containerd.grpc.v1.cri", "registry"},
+						},
+					},
+				},
+			}
+		})
+
+		It("should write the registry, sandbox image and plugin configuration of the OSC", func() {
+			userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var ign ignitionTestConfig
+			Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+
+			By("writing hosts.toml files for registries without readiness probe")
+			Expect(ign.Storage.Files).To(ContainElement(SatisfyAll(
+				HaveField("Path", "/etc/containerd/certs.d/docker.io/hosts.toml"),
+				HaveField("Contents.Source", "data:;base64,"+base64.StdEncoding.EncodeToString([]byte(`
+server = "https://registry-1.docker.io"
+
+[host."https://mirror.example.com"]
+  capabilities = ["pull","resolve"]
+
+[host."https://private.example.com"]
+  capabilities = ["pull"]
+  ca = ["/etc/ssl/private-ca.pem"]
+  override_path = true
+
+`))),
+				HaveField("Mode", ptr.To(0o644)),
+			)))
+			Expect(ign.Storage.Files).NotTo(ContainElement(HaveField("Path", "/etc/containerd/certs.d/in-cluster.local/hosts.toml")))
+
+			By("writing the sandbox image and plugin configuration into the containerd config")
+			Expect(ign.Storage.Files).To(ContainElement(SatisfyAll(
+				HaveField("Path", "/etc/containerd/config.toml"),
+				HaveField("Contents.Source", "data:;base64,"+base64.StdEncoding.EncodeToString([]byte(`imports = ["/etc/containerd/conf.d/*.toml"]
+version = 2
+
+[plugins]
+  [plugins."io.containerd.grpc.v1.cri"]
+    sandbox_image = "europe-docker.pkg.dev/gardener-project/releases/pause:3.10"
+    [plugins."io.containerd.grpc.v1.cri".containerd]
+      default_runtime_name = "runc"
+      snapshotter = "overlayfs"
+      [plugins."io.containerd.grpc.v1.cri".containerd.runtimes]
+        [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+          [plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+            BinaryName = "/usr/bin/crun"
+            SystemdCgroup = true
+`))),
+			)))
+		})
+
+		It("should translate plugin paths for containerd config version 3", func() {
+			oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+				Containerd: &coreosconfig.ContainerdConfig{ConfigVersion: ptr.To[int32](3)},
+			}})
+			osc.Spec.CRIConfig.Containerd.Plugins = osc.Spec.CRIConfig.Containerd.Plugins[:1]
+			userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var ign ignitionTestConfig
+			Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+			Expect(ign.Storage.Files).To(ContainElement(SatisfyAll(
+				HaveField("Path", "/etc/containerd/config.toml"),
+				HaveField("Contents.Source", "data:;base64,"+base64.StdEncoding.EncodeToString([]byte(`imports = ["/etc/containerd/conf.d/*.toml"]
+version = 3
+
+[plugins]
+  [plugins."io.containerd.cri.v1.images"]
+    snapshotter = "overlayfs"
+    [plugins."io.containerd.cri.v1.images".pinned_images]
+      sandbox = "europe-docker.pkg.dev/gardener-project/releases/pause:3.10"
+    [plugins."io.containerd.cri.v1.images".registry]
+      config_path = "/etc/containerd/certs.d"
+  [plugins."io.containerd.cri.v1.runtime"]
+    [plugins."io.containerd.cri.v1.runtime".containerd]
+      default_runtime_name = "runc"
+      [plugins."io.containerd.cri.v1.runtime".containerd.runtimes]
+        [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc]
+          runtime_type = "io.containerd.runc.v2"
+          [plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc.options]
+            BinaryName = "/usr/bin/crun"
+            SystemdCgroup = true
+`))),
+			)))
+		})
+	})
+
+	When("purpose is 'provision'", func() {
+		It("should write the registry credentials from a docker config secret", func() {
+			Expect(fakeClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "ref-registry-credentials", Namespace: osc.Namespace},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{"https://registry.example.com/v2/":{"username":"foo","password":"bar"}}}`),
+				},
+			})).To(Succeed())
+			oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+				Containerd: &coreosconfig.ContainerdConfig{
+					ConfigVersion: ptr.To[int32](3),
+					RegistryAuth: []coreosconfig.RegistryAuth{{
+						Registry:  "registry.example.com",
+						SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"},
+					}},
+				},
+			}})
+			userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+
+			var ign ignitionTestConfig
+			Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+			Expect(ign.Storage.Files).To(ContainElement(SatisfyAll(
+				HaveField("Path", "/etc/containerd/conf.d/registry-auth.toml"),
+				HaveField("Contents.Source", "data:;base64,"+base64.StdEncoding.EncodeToString([]byte(`version = 3
+
+[plugins]
+  [plugins."io.containerd.cri.v1.images"]
+    [plugins."io.containerd.cri.v1.images".registry]
+      [plugins."io.containerd.cri.v1.images".registry.configs]
+        [plugins."io.containerd.cri.v1.images".registry.configs."registry.example.com"]
+          [plugins."io.containerd.cri.v1.images".registry.configs."registry.example.com".auth]
+            username = "foo"
+            password = "bar"
+`))),
+				HaveField("Mode", ptr.To(0o600)),
+			)))
+		})
+
+		It("should fail without exposing the credentials if the secret does not contain the registry", func() {
+			Expect(fakeClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "ref-registry-credentials", Namespace: osc.Namespace},
+				Type:       corev1.SecretTypeDockerConfigJson,
+				Data: map[string][]byte{
+					corev1.DockerConfigJsonKey: []byte(`{"auths":{"registry.example.org":{"auth":"c2VjcmV0"}}}`),
+				},
+			})).To(Succeed())
+			oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+				Containerd: &coreosconfig.ContainerdConfig{
+					ConfigVersion: ptr.To[int32](3),
+					RegistryAuth: []coreosconfig.RegistryAuth{{
+						Registry:  "registry.example.com",
+						SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"},
+					}},
+				},
+			}})
+			_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).To(MatchError(ContainSubstring(`failed to read credentials for registry "registry.example.com" from secret "ref-registry-credentials"`)))
+			Expect(err.Error()).NotTo(ContainSubstring("c2VjcmV0"))
+		})
+
+		It("should report the applied profile", func() {
+			oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+				NTP:      &coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.SystemdTimesyncd},
+				Profiles: []coreosconfig.ConfigProfile{{Name: "legacy-docker", Config: coreosconfig.ExtensionConfig{EnableDocker: ptr.To(true)}}},
+				Profile:  "legacy-docker",
+			}})
+			_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(Equal(`Normal ConfigurationProfileApplied Applied configuration profile "legacy-docker"`)))
+
+			By("not reporting the profile again if it did not change")
+			_, _, _, _, err = oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).NotTo(Receive())
+		})
+
+		It("should report the applied profile again if it changed", func() {
+			oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+				NTP: &coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.SystemdTimesyncd},
+				Profiles: []coreosconfig.ConfigProfile{
+					{Name: "legacy-docker", Config: coreosconfig.ExtensionConfig{EnableDocker: ptr.To(true)}},
+					{Name: "hardened", Config: coreosconfig.ExtensionConfig{Sysctl: &coreosconfig.SysctlConfig{Profiles: []coreosconfig.SysctlProfile{"hardened"}}}},
+				},
+				Profile: "legacy-docker",
+			}})
+			_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(Equal(`Normal ConfigurationProfileApplied Applied configuration profile "legacy-docker"`)))
+
+			osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","profile":"hardened"}`)}
+			_, _, _, _, err = oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(Equal(`Normal ConfigurationProfileApplied Applied configuration profile "hardened"`)))
+
+			By("reporting the profile again after the operating system config was deleted")
+			Expect(oscActuator.Delete(ctx, log, osc)).To(Succeed())
+			_, _, _, _, err = oscActuator.Reconcile(ctx, log, osc)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(Equal(`Normal ConfigurationProfileApplied Applied configuration profile "hardened"`)))
+		})
+
+		Context("image version rules", func() {
+			const (
+				detectLogrotatePath = `if [ -f "$ALTERNATE_LOGROTATE_PATH" ]; then`
+				fixLogrotatePath    = "\nsed -i \"s;/usr/sbin/logrotate;$ALTERNATE_LOGROTATE_PATH;\" /etc/systemd/system/containerd-logrotate.service"
+			)
+
+			var provision = func() ignitionTestConfig {
+				userData, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				var ign ignitionTestConfig
+				Expect(stdjson.Unmarshal(userData, &ign)).To(Succeed())
+				return ign
+			}
+			var containerdSetupScript = func(ign ignitionTestConfig) string {
+				for _, f := range ign.Storage.Files {
+					if f.Path == "/opt/bin/containerd-setup.sh" {
+						data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(f.Contents.Source, "data:;base64,"))
+						Expect(err).NotTo(HaveOccurred())
+						return string(data)
+					}
+				}
+				Fail("containerd setup script not found")
+				return ""
+			}
+
+			BeforeEach(func() {
+				Expect(fakeClient.Delete(ctx, &extensionsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--bar"}})).To(Succeed())
+				Expect(fakeClient.Create(ctx, newCluster("shoot--foo--bar", "local",
+					gardencorev1beta1.Worker{Name: "lts", Machine: gardencorev1beta1.Machine{Image: &gardencorev1beta1.ShootMachineImage{Name: "flatcar", Version: ptr.To("3510.3.2")}}},
+					gardencorev1beta1.Worker{Name: "alpha", Machine: gardencorev1beta1.Machine{Image: &gardencorev1beta1.ShootMachineImage{Name: "flatcar", Version: ptr.To("4230.0.0")}}},
+				))).To(Succeed())
+				osc.Labels = map[string]string{"worker.gardener.cloud/pool": "alpha"}
+
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.SystemdTimesyncd},
+					ImageVersionRules: []coreosconfig.ImageVersionRule{{
+						Versions: ">= 4000",
+						Enable:   []coreosconfig.Customization{coreosconfig.CustomizationLogrotatePath},
+						Disable:  []coreosconfig.Customization{coreosconfig.CustomizationMaskSysupdate},
+					}},
+				}})
+			})
+
+			It("should apply the customizations of the rules matching the image version of the worker pool", func() {
+				ign := provision()
+				Expect(containerdSetupScript(ign)).To(SatisfyAll(ContainSubstring(fixLogrotatePath), Not(ContainSubstring(detectLogrotatePath))))
+				Expect(ign.Storage.Links).NotTo(ContainElement(HaveField("Path", "/etc/systemd/system/systemd-sysupdate.timer")))
+				Expect(ign.Storage.Links).To(ContainElement(HaveField("Path", "/etc/systemd/system/update-engine.service")))
+			})
+
+			It("should keep the defaults if no rule matches the image version", func() {
+				osc.Labels["worker.gardener.cloud/pool"] = "lts"
+				ign := provision()
+				Expect(containerdSetupScript(ign)).To(SatisfyAll(ContainSubstring(detectLogrotatePath), Not(ContainSubstring(fixLogrotatePath))))
+				Expect(ign.Storage.Links).To(ContainElement(HaveField("Path", "/etc/systemd/system/systemd-sysupdate.timer")))
+			})
+
+			It("should keep the defaults if the worker pool is unknown", func() {
+				osc.Labels = nil
+				Expect(containerdSetupScript(provision())).To(ContainSubstring(detectLogrotatePath))
+			})
+
+			It("should let later rules take precedence", func() {
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.SystemdTimesyncd},
+					ImageVersionRules: []coreosconfig.ImageVersionRule{
+						{Versions: ">= 3000", Enable: []coreosconfig.Customization{coreosconfig.CustomizationLogrotatePath}},
+						{Versions: ">= 4000", Disable: []coreosconfig.Customization{coreosconfig.CustomizationLogrotatePath}},
+					},
+				}})
+				Expect(containerdSetupScript(provision())).NotTo(ContainSubstring("logrotate.service"))
+			})
+		})
+	})
+
 	When("purpose is 'reconcile'", func() {
 		BeforeEach(func() {
 			osc.Spec.Purpose = extensionsv1alpha1.OperatingSystemConfigPurposeReconcile
+			osc.Spec.Units = append(osc.Spec.Units, extensionsv1alpha1.Unit{Name: "kubelet.service", Content: ptr.To(`[Unit]
+Description=kubelet daemon
+[Service]
+EnvironmentFile=/etc/environment
+EnvironmentFile=-/var/lib/kubelet/extra_args
+ExecStart=/opt/bin/kubelet \
+    --bootstrap-kubeconfig=/var/lib/kubelet/kubeconfig-bootstrap \
+    --config=/var/lib/kubelet/config/kubelet $KUBELET_EXTRA_ARGS
+`)})
 		})
 
 		Describe("#Reconcile", func() {
 			It("should override global defaults on a shoot by shoot basis and enable ntpd for that shoot", func() {
 				// Shoot specific override via providerConfig
-				providerConfigData := configv1alpha1.ExtensionConfig{
-					NTP: &configv1alpha1.NTPConfig{
-						Daemon:  configv1alpha1.NTPD,
+				providerConfigData := coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Daemon:  coreosconfig.NTPD,
 						Enabled: ptr.To(true),
-						NTPD: &configv1alpha1.NTPDConfig{
+						NTPD: &coreosconfig.NTPDConfig{
 							Servers: []string{"foo.bar", "bar.foo"},
 						},
 					},
@@ -362,15 +1258,89 @@ var _ = Describe("Actuator", func() {
 					osc.Spec.ProviderConfig = nil
 				})
 				Expect(err).NotTo(HaveOccurred())
-				userData, extensionUnits, _, _, err := actuator.Reconcile(ctx, log, osc)
+				userData, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(userData).To(BeEmpty())
 				Expect(providerConfigData).To(Not(Equal(*globalExtensionConfig)))
 				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/ntp.conf"}}))
 			})
+			It("should accept a v1beta1 provider config", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","ntp":{"daemon":"ntpd","ntpd":{"servers":["foo.bar"]}}}`)}
+				_, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/ntp.conf"}}))
+			})
+			It("should decode a provider config without apiVersion and kind as v1alpha1", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"ntp":{"daemon":"ntpd","ntpd":{"servers":["foo.bar"]}}}`)}
+				_, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/ntp.conf"}}))
+			})
+			It("should return a configuration problem for an invalid provider config", func() {
+				providerConfigData := coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Daemon:  "foo",
+						Enabled: ptr.To(true),
+					},
+				}
+				providerConfigBuffer := new(bytes.Buffer)
+				Expect(encoder.Encode(&providerConfigData, providerConfigBuffer)).To(Succeed())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()}
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("ntp.daemon")))
+				Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+			})
+			It("should return a configuration problem if the provider config violates the policy", func() {
+				extensionConfig := globalExtensionConfig.DeepCopy()
+				extensionConfig.Policy = &coreosconfig.PolicyConfig{
+					LockedFields:  []string{"enableDocker"},
+					AllowedValues: []coreosconfig.AllowedValues{{Field: "ntp.daemon", Values: []string{"chrony"}}},
+				}
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: extensionConfig})
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","enableDocker":true,"ntp":{"daemon":"ntpd","ntpd":{"servers":["foo.bar"]}}}`)}
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(SatisfyAll(
+					ContainSubstring("provider config violates the policy of the extension config"),
+					ContainSubstring("enableDocker: Forbidden: field is locked by the extension config"),
+					ContainSubstring(`ntp.daemon: Unsupported value: "ntpd": supported values: "chrony"`),
+				)))
+				Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+			})
+			It("should return a configuration problem if the provider config cannot be decoded", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1alpha1","kind":"ExtensionConfig","ntp":"foo"}`)}
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("failed to decode provider config")))
+				Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+			})
+			It("should return a configuration problem for unknown fields in the provider config", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1alpha1","kind":"ExtensionConfig","enableDockr":true,"ntp":{"servers":["foo.bar"]}}`)}
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(SatisfyAll(ContainSubstring(`enableDockr: Forbidden: unknown field`), ContainSubstring(`ntp.servers: Forbidden: unknown field`))))
+				Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+			})
+			It("should ignore unknown fields in the provider config with lenient decoding", func() {
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: globalExtensionConfig, LenientDecoding: true})
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1alpha1","kind":"ExtensionConfig","enableDockr":true,"ntp":{"daemon":"ntpd","ntpd":{"servers":["foo.bar"]}}}`)}
+				_, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/ntp.conf"}}))
+			})
+			It("should return a configuration problem if the merged config is invalid", func() {
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					NTP:        &coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.SystemdTimesyncd},
+					Containerd: &coreosconfig.ContainerdConfig{ConfigVersion: ptr.To[int32](4)},
+				}})
+				providerConfigData := coreosconfig.ExtensionConfig{EnableDocker: ptr.To(true)}
+				providerConfigBuffer := new(bytes.Buffer)
+				Expect(encoder.Encode(&providerConfigData, providerConfigBuffer)).To(Succeed())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()}
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("invalid configuration after merging the provider config")))
+				Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+			})
 			It("should override global default with nothing", func() {
-				providerConfigData := configv1alpha1.ExtensionConfig{
-					NTP: &configv1alpha1.NTPConfig{
+				providerConfigData := coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
 						Enabled: ptr.To(false),
 					},
 				}
@@ -381,7 +1351,7 @@ var _ = Describe("Actuator", func() {
 					osc.Spec.ProviderConfig = nil
 				})
 				Expect(err).NotTo(HaveOccurred())
-				userData, extensionUnits, _, _, err := actuator.Reconcile(ctx, log, osc)
+				userData, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(userData).To(BeEmpty())
 				Expect(extensionUnits).To(Not(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true)})))
@@ -391,14 +1361,14 @@ var _ = Describe("Actuator", func() {
 			})
 			It("should not enable any timesync service when Daemon is None", func() {
 				extensionConfig := Config{
-					ExtensionConfig: &configv1alpha1.ExtensionConfig{
-						NTP: &configv1alpha1.NTPConfig{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
 							Enabled: ptr.To(false),
 						},
 					},
 				}
-				actuator = NewActuator(mgr, extensionConfig)
-				userData, extensionUnits, _, _, err := actuator.Reconcile(ctx, log, osc)
+				oscActuator = NewActuator(mgr, extensionConfig)
+				userData, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(userData).To(BeEmpty())
 				Expect(extensionUnits).To(Not(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true)})))
@@ -408,19 +1378,19 @@ var _ = Describe("Actuator", func() {
 			})
 			It("should enable ntpd service", func() {
 				extensionConfig := Config{
-					ExtensionConfig: &configv1alpha1.ExtensionConfig{
-						NTP: &configv1alpha1.NTPConfig{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
 							Enabled: ptr.To(true),
-							Daemon:  configv1alpha1.NTPD,
-							NTPD: &configv1alpha1.NTPDConfig{
+							Daemon:  coreosconfig.NTPD,
+							NTPD: &coreosconfig.NTPDConfig{
 								Servers:    []string{"foo.bar", "bar.foo"},
 								Interfaces: []string{"dev1", "dev2"},
 							},
 						},
 					},
 				}
-				actuator = NewActuator(mgr, extensionConfig)
-				userData, extensionUnits, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+				oscActuator = NewActuator(mgr, extensionConfig)
+				userData, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(userData).To(BeEmpty())
 				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{filepath.Join(string(filepath.Separator), "etc", "ntp.conf")}}))
@@ -442,19 +1412,571 @@ interface ignore wildcard
 interface listen 127.0.0.1
 interface listen dev1
 interface listen dev2
+`,
+						},
+					},
+				}))
+			})
+			It("should enable chronyd and stop the other daemons", func() {
+				extensionConfig := Config{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.Chrony,
+							Chrony: &coreosconfig.ChronyConfig{
+								Servers: []coreosconfig.ChronySource{{Address: "foo.bar"}},
+								Pools:   []coreosconfig.ChronySource{{Address: "pool.ntp.org"}},
+								Allow:   []string{"10.0.0.0/8"},
+							},
+						},
+					},
+				}
+				oscActuator = NewActuator(mgr, extensionConfig)
+				_, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElements(
+					extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+					extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+					extensionsv1alpha1.Unit{Name: "chronyd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/chrony/chrony.conf"}},
+				))
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/chrony/chrony.conf",
+					Permissions: ptr.To[uint32](0644),
+					Content: extensionsv1alpha1.FileContent{
+						Inline: &extensionsv1alpha1.FileContentInline{
+							Data: `server foo.bar iburst
+pool pool.ntp.org iburst
+
+driftfile /var/lib/chrony/drift
+makestep 1 3
+rtcsync
+allow 10.0.0.0/8
+`,
+						},
+					},
+				}))
+				Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", "/etc/ntp.conf")))
+			})
+			It("should render the makestep and rtcsync settings of chrony", func() {
+				extensionConfig := Config{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.Chrony,
+							Chrony: &coreosconfig.ChronyConfig{
+								Servers:  []coreosconfig.ChronySource{{Address: "foo.bar"}},
+								MakeStep: &coreosconfig.ChronyMakeStep{Threshold: metav1.Duration{Duration: 500 * time.Millisecond}, Limit: -1},
+								RTCSync:  ptr.To(false),
+							},
+						},
+					},
+				}
+				oscActuator = NewActuator(mgr, extensionConfig)
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(HaveField("Content.Inline.Data", `server foo.bar iburst
+
+driftfile /var/lib/chrony/drift
+makestep 0.5 -1
+`)))
+			})
+			It("should render NTS sources and the trusted certificates of chrony", func() {
+				extensionConfig := Config{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
+							Enabled:               ptr.To(true),
+							Daemon:                coreosconfig.Chrony,
+							RequireAuthentication: ptr.To(true),
+							Chrony: &coreosconfig.ChronyConfig{
+								Servers:                []coreosconfig.ChronySource{{Address: "nts.example.com", NTS: true}},
+								NTSTrustedCertificates: ptr.To("-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"),
+							},
+						},
+					},
+				}
+				oscActuator = NewActuator(mgr, extensionConfig)
+				_, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "chronyd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/chrony/chrony.conf", "/etc/chrony/nts-trusted-certs.pem"}}))
+				Expect(extensionFiles).To(ContainElements(
+					extensionsv1alpha1.File{
+						Path:        "/etc/chrony/chrony.conf",
+						Permissions: ptr.To[uint32](0644),
+						Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `server nts.example.com iburst nts
+
+driftfile /var/lib/chrony/drift
+makestep 1 3
+rtcsync
+ntsdumpdir /var/lib/chrony
+ntstrustedcerts /etc/chrony/nts-trusted-certs.pem
+authselectmode require
+`}},
+					},
+					extensionsv1alpha1.File{
+						Path:        "/etc/chrony/nts-trusted-certs.pem",
+						Permissions: ptr.To[uint32](0644),
+						Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"}},
+					},
+				))
+			})
+			It("should authenticate the ntpd servers with the key of the secret", func() {
+				Expect(fakeClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "ntp-key", Namespace: osc.Namespace},
+					Data:       map[string][]byte{"key": []byte("0123456789abcdef0123456789abcdef01234567")},
+				})).To(Succeed())
+				extensionConfig := Config{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.NTPD,
+							NTPD: &coreosconfig.NTPDConfig{
+								Servers:        []string{"foo.bar"},
+								Authentication: &coreosconfig.NTPDAuthentication{KeyID: 42, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "ntp-key"}},
+							},
+						},
+					},
+				}
+				oscActuator = NewActuator(mgr, extensionConfig)
+				_, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/ntp.conf", "/etc/ntp.keys"}}))
+				Expect(extensionFiles).To(ContainElements(
+					extensionsv1alpha1.File{
+						Path:        "/etc/ntp.conf",
+						Permissions: ptr.To[uint32](0644),
+						Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `
+server foo.bar iburst key 42
+
+driftfile /var/lib/ntp/ntp.drift
+keys /etc/ntp.keys
+trustedkey 42
+restrict default nomodify nopeer noquery notrap limited kod
+restrict 127.0.0.1
+restrict [::1]
+
+`}},
+					},
+					extensionsv1alpha1.File{
+						Path:        "/etc/ntp.keys",
+						Permissions: ptr.To[uint32](0600),
+						Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "42 SHA1 0123456789abcdef0123456789abcdef01234567\n"}},
+					},
+				))
+			})
+			It("should render ntpd sources with options, restrict lines and the drift file", func() {
+				extensionConfig := Config{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.NTPD,
+							NTPD: &coreosconfig.NTPDConfig{
+								Servers: []string{"foo.bar"},
+								Sources: []coreosconfig.NTPDSource{
+									{Address: "ntp1.example.com", Prefer: true, MinPoll: ptr.To[int32](4), MaxPoll: ptr.To[int32](10)},
+									{Address: "pool.ntp.org", Pool: true},
+								},
+								Restrict:  []string{"default kod nomodify notrap nopeer noquery", "127.0.0.1"},
+								DriftFile: ptr.To("/var/lib/ntp/drift"),
+							},
+						},
+					},
+				}
+				oscActuator = NewActuator(mgr, extensionConfig)
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/ntp.conf",
+					Permissions: ptr.To[uint32](0644),
+					Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `
+server foo.bar iburst
+server ntp1.example.com iburst prefer minpoll 4 maxpoll 10
+pool pool.ntp.org iburst
+
+driftfile /var/lib/ntp/drift
+restrict default kod nomodify notrap nopeer noquery
+restrict 127.0.0.1
+
+`}},
+				}))
+			})
+			It("should fail without exposing an invalid ntpd key", func() {
+				Expect(fakeClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "ntp-key", Namespace: osc.Namespace},
+					Data:       map[string][]byte{"key": []byte("secret value")},
+				})).To(Succeed())
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD: &coreosconfig.NTPDConfig{
+							Servers:        []string{"foo.bar"},
+							Authentication: &coreosconfig.NTPDAuthentication{KeyID: 1, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "ntp-key"}},
+						},
+					},
+				}})
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring(`secret "ntp-key"`)))
+				Expect(err.Error()).NotTo(ContainSubstring("secret value"))
+			})
+			It("should refuse ntpd keys from secrets the shoot does not reference", func() {
+				providerConfigBuffer := new(bytes.Buffer)
+				Expect(encoder.Encode(&coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Daemon: coreosconfig.NTPD,
+						NTPD: &coreosconfig.NTPDConfig{
+							Servers:        []string{"foo.bar"},
+							Authentication: &coreosconfig.NTPDAuthentication{KeyID: 1, Type: "SHA1", SecretRef: corev1.LocalObjectReference{Name: "cloudprovider"}},
+						},
+					},
+				}, providerConfigBuffer)).To(Succeed())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()}
+
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring(`ntp.ntpd.authentication.secretRef.name: Invalid value: "cloudprovider": must start with "ref-"`)))
+			})
+			It("should configure the servers of systemd-timesyncd", func() {
+				extensionConfig := Config{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(true),
+							Daemon:  coreosconfig.SystemdTimesyncd,
+							Timesyncd: &coreosconfig.TimesyncdConfig{
+								Servers:         []string{"foo.bar", "bar.foo"},
+								FallbackServers: []string{"pool.ntp.org"},
+								PollIntervalMin: &metav1.Duration{Duration: 64 * time.Second},
+								PollIntervalMax: &metav1.Duration{Duration: time.Hour},
+							},
+						},
+					},
+				}
+				oscActuator = NewActuator(mgr, extensionConfig)
+				_, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/systemd/timesyncd.conf.d/10-os-coreos.conf"}}))
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/systemd/timesyncd.conf.d/10-os-coreos.conf",
+					Permissions: ptr.To[uint32](0644),
+					Content: extensionsv1alpha1.FileContent{
+						Inline: &extensionsv1alpha1.FileContentInline{
+							Data: `[Time]
+NTP=foo.bar bar.foo
+FallbackNTP=pool.ntp.org
+PollIntervalMinSec=64
+PollIntervalMaxSec=3600
+`,
+						},
+					},
+				}))
+			})
+			It("should default the servers of systemd-timesyncd to the time service of the cloud provider", func() {
+				osc.Namespace = "shoot--foo--aws"
+				Expect(fakeClient.Create(ctx, newCluster(osc.Namespace, "aws"))).To(Succeed())
+				_, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true), FilePaths: []string{"/etc/systemd/timesyncd.conf.d/10-os-coreos.conf"}}))
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/systemd/timesyncd.conf.d/10-os-coreos.conf",
+					Permissions: ptr.To[uint32](0644),
+					Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "[Time]\nNTP=169.254.169.123\n"}},
+				}))
+				Expect(globalExtensionConfig.NTP.Timesyncd).To(BeNil())
+			})
+			It("should default chrony to the PTP clock of the hypervisor on azure", func() {
+				osc.Namespace = "shoot--foo--azure"
+				Expect(fakeClient.Create(ctx, newCluster(osc.Namespace, "azure"))).To(Succeed())
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{Enabled: ptr.To(true), Daemon: coreosconfig.Chrony},
+				}})
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/chrony/chrony.conf",
+					Permissions: ptr.To[uint32](0644),
+					Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `refclock PHC /dev/ptp_hyperv poll 3 dpoll -2
+
+driftfile /var/lib/chrony/drift
+makestep 1 3
+rtcsync
+`}},
+				}))
+			})
+			It("should not default the time sources of the cloud provider if they are configured explicitly", func() {
+				osc.Namespace = "shoot--foo--aws"
+				Expect(fakeClient.Create(ctx, newCluster(osc.Namespace, "aws"))).To(Succeed())
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled: ptr.To(true),
+						Daemon:  coreosconfig.NTPD,
+						NTPD:    &coreosconfig.NTPDConfig{Servers: []string{"foo.bar"}},
+					},
+				}})
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(And(
+					HaveField("Path", "/etc/ntp.conf"),
+					HaveField("Content.Inline.Data", And(ContainSubstring("server foo.bar iburst"), Not(ContainSubstring("169.254.169.123")))),
+				)))
+			})
+			It("should return a configuration problem if ntpd has neither servers nor time sources of the cloud provider", func() {
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","ntp":{"daemon":"ntpd"}}`)}
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("ntp.ntpd: Required value: ntpd config is required with daemon ntpd")))
+				Expect(v1beta1helper.ExtractErrorCodes(err)).To(ConsistOf(gardencorev1beta1.ErrorConfigurationProblem))
+			})
+			It("should default the servers of ntpd to the time service of the cloud provider", func() {
+				osc.Namespace = "shoot--foo--aws"
+				Expect(fakeClient.Create(ctx, newCluster(osc.Namespace, "aws"))).To(Succeed())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: []byte(`{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","ntp":{"daemon":"ntpd"}}`)}
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(And(
+					HaveField("Path", "/etc/ntp.conf"),
+					HaveField("Content.Inline.Data", ContainSubstring("server 169.254.169.123 iburst")),
+				)))
+			})
+			It("should fail if the cluster does not exist", func() {
+				osc.Namespace = "shoot--foo--unknown"
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("error getting cluster")))
+			})
+			It("should delay the kubelet until chrony reports a synchronized clock", func() {
+				oscActuator = NewActuator(mgr, Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+					NTP: &coreosconfig.NTPConfig{
+						Enabled:     ptr.To(true),
+						Daemon:      coreosconfig.Chrony,
+						WaitForSync: &coreosconfig.NTPWaitForSync{Enabled: true},
+					},
+				}})
+				_, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElements(
+					extensionsv1alpha1.Unit{Name: "systemd-time-wait-sync.service", Enable: ptr.To(false)},
+					SatisfyAll(
+						HaveField("Name", "chrony-wait.service"),
+						HaveField("Enable", ptr.To(true)),
+						HaveField("Command", BeNil()),
+						HaveField("Content", HaveValue(ContainSubstring("ExecStart=/usr/bin/chronyc -h 127.0.0.1,::1 waitsync 0 0.1 0.0 1"))),
+						HaveField("DropIns", ConsistOf(extensionsv1alpha1.DropIn{Name: "10-timeout.conf", Content: "[Service]\nTimeoutStartSec=120\n"})),
+					),
+					SatisfyAll(
+						HaveField("Name", "kubelet.service"),
+						HaveField("DropIns", ContainElement(extensionsv1alpha1.DropIn{Name: "30-wait-for-time-sync.conf", Content: "[Unit]\nWants=time-sync.target\nAfter=time-sync.target\n"})),
+					),
+				))
+			})
+			It("should configure the kubelet with the cgroup driver from the CRI config", func() {
+				osc.Spec.CRIConfig = &extensionsv1alpha1.CRIConfig{
+					Name:         extensionsv1alpha1.CRINameContainerD,
+					CgroupDriver: ptr.To(extensionsv1alpha1.CgroupDriverCgroupfs),
+				}
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/kubernetes/kubelet.conf.d/10-os-coreos-cgroup-driver.conf",
+					Permissions: ptr.To[uint32](0644),
+					Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+cgroupDriver: cgroupfs
+`}},
+				}))
+				Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", "/opt/bin/kubelet_cgroup_driver.sh")))
+			})
+			It("should take the cgroup driver from the capabilities of the machine image", func() {
+				cluster := newCluster("shoot--foo--bar", "local",
+					gardencorev1beta1.Worker{Name: "pool", Machine: gardencorev1beta1.Machine{Image: &gardencorev1beta1.ShootMachineImage{Name: "flatcar", Version: ptr.To("3510.3.2")}}},
+				)
+				cloudProfile := &gardencorev1beta1.CloudProfile{
+					TypeMeta: metav1.TypeMeta{APIVersion: gardencorev1beta1.SchemeGroupVersion.String(), Kind: "CloudProfile"},
+					Spec: gardencorev1beta1.CloudProfileSpec{
+						MachineCapabilities: []gardencorev1beta1.CapabilityDefinition{
+							{Name: "architecture", Values: []string{"amd64"}},
+							{Name: CapabilityCgroupVersion, Values: []string{"v2", "v1"}},
+						},
+						MachineImages: []gardencorev1beta1.MachineImage{{
+							Name: "flatcar",
+							Versions: []gardencorev1beta1.MachineImageVersion{{
+								ExpirableVersion:  gardencorev1beta1.ExpirableVersion{Version: "3510.3.2"},
+								CapabilityFlavors: []gardencorev1beta1.MachineImageFlavor{{Capabilities: gardencorev1beta1.Capabilities{CapabilityCgroupVersion: {"v1"}}}},
+							}},
+						}},
+					},
+				}
+				raw, err := stdjson.Marshal(cloudProfile)
+				Expect(err).NotTo(HaveOccurred())
+				cluster.Spec.CloudProfile = runtime.RawExtension{Raw: raw}
+				Expect(fakeClient.Delete(ctx, &extensionsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--bar"}})).To(Succeed())
+				Expect(fakeClient.Create(ctx, cluster)).To(Succeed())
+				osc.Labels = map[string]string{"worker.gardener.cloud/pool": "pool"}
+
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(SatisfyAll(
+					HaveField("Path", "/etc/kubernetes/kubelet.conf.d/10-os-coreos-cgroup-driver.conf"),
+					HaveField("Content.Inline.Data", ContainSubstring("cgroupDriver: cgroupfs")),
+				)))
+			})
+			It("should pass the configuration drop-in directory to the kubelet without taking over KUBELET_EXTRA_ARGS", func() {
+				_, extensionUnits, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(SatisfyAll(
+					HaveField("Name", "kubelet.service"),
+					HaveField("DropIns", ContainElement(extensionsv1alpha1.DropIn{
+						Name: "10-configure-cgroup-driver.conf",
+						Content: `[Service]
+ExecStart=
+ExecStart=/opt/bin/kubelet \
+    --bootstrap-kubeconfig=/var/lib/kubelet/kubeconfig-bootstrap \
+    --config=/var/lib/kubelet/config/kubelet --config-dir=/etc/kubernetes/kubelet.conf.d $KUBELET_EXTRA_ARGS
+`,
+					})),
+				)))
+			})
+			It("should fail if the kubelet unit has no command line", func() {
+				osc.Spec.Units[len(osc.Spec.Units)-1].Content = ptr.To("[Unit]\nDescription=kubelet daemon\n[Service]\nEnvironmentFile=/etc/environment\n")
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("the operating system config has no kubelet.service unit with an ExecStart command")))
+			})
+			It("should fail if there is no kubelet unit", func() {
+				osc.Spec.Units = osc.Spec.Units[:len(osc.Spec.Units)-1]
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("the operating system config has no kubelet.service unit with an ExecStart command")))
+			})
+			It("should leave the registry configuration of the OSC to gardener-node-agent", func() {
+				osc.Spec.CRIConfig = &extensionsv1alpha1.CRIConfig{
+					Name: extensionsv1alpha1.CRINameContainerD,
+					Containerd: &extensionsv1alpha1.ContainerdConfig{
+						Registries: []extensionsv1alpha1.RegistryConfig{{
+							Upstream: "_default",
+							Hosts:    []extensionsv1alpha1.RegistryHost{{URL: "https://mirror.example.com"}},
+						}},
+					},
+				}
+				_, _, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).NotTo(ContainElement(HaveField("Path", HavePrefix("/etc/containerd/certs.d/"))))
+			})
+			It("should write the registry credentials and restart containerd when they change", func() {
+				Expect(fakeClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "ref-registry-credentials", Namespace: osc.Namespace},
+					Data:       map[string][]byte{"username": []byte("foo"), "password": []byte("bar")},
+				})).To(Succeed())
+				providerConfigBuffer := new(bytes.Buffer)
+				Expect(encoder.Encode(&coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+						RegistryAuth: []coreosconfig.RegistryAuth{{
+							Registry:  "registry.example.com:5000",
+							SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"},
+						}},
+					},
+				}, providerConfigBuffer)).To(Succeed())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()}
+
+				_, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/containerd/conf.d/registry-auth.toml",
+					Permissions: ptr.To[uint32](0600),
+					Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `version = 3
+
+[plugins]
+  [plugins."io.containerd.cri.v1.images"]
+    [plugins."io.containerd.cri.v1.images".registry]
+      [plugins."io.containerd.cri.v1.images".registry.configs]
+        [plugins."io.containerd.cri.v1.images".registry.configs."registry.example.com:5000"]
+          [plugins."io.containerd.cri.v1.images".registry.configs."registry.example.com:5000".auth]
+            username = "foo"
+            password = "bar"
+`}},
+				}))
+				Expect(extensionUnits).To(ContainElement(SatisfyAll(
+					HaveField("Name", "containerd.service"),
+					HaveField("FilePaths", ConsistOf("/etc/containerd/conf.d/registry-auth.toml")),
+				)))
+			})
+			It("should refuse registry credentials from secrets the shoot does not reference", func() {
+				providerConfigBuffer := new(bytes.Buffer)
+				Expect(encoder.Encode(&coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						ConfigVersion: ptr.To[int32](3),
+						RegistryAuth: []coreosconfig.RegistryAuth{{
+							Registry:  "registry.example.com",
+							SecretRef: corev1.LocalObjectReference{Name: "cloudprovider"},
+						}},
+					},
+				}, providerConfigBuffer)).To(Succeed())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()}
+
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring(`containerd.registryAuth[0].secretRef.name: Invalid value: "cloudprovider": must start with "ref-"`)))
+			})
+			It("should refuse registry credentials with containerd config version 2", func() {
+				providerConfigBuffer := new(bytes.Buffer)
+				Expect(encoder.Encode(&coreosconfig.ExtensionConfig{
+					Containerd: &coreosconfig.ContainerdConfig{
+						RegistryAuth: []coreosconfig.RegistryAuth{{
+							Registry:  "registry.example.com",
+							SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"},
+						}},
+					},
+				}, providerConfigBuffer)).To(Succeed())
+				osc.Spec.ProviderConfig = &runtime.RawExtension{Raw: providerConfigBuffer.Bytes()}
+
+				_, _, _, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).To(MatchError(ContainSubstring("registry credentials require containerd config version 3, but version 2 is used")))
+			})
+			It("should render sysctl profiles and settings and restart systemd-sysctl", func() {
+				extensionConfig := Config{
+					ExtensionConfig: &coreosconfig.ExtensionConfig{
+						NTP: &coreosconfig.NTPConfig{
+							Enabled: ptr.To(false),
+						},
+						Sysctl: &coreosconfig.SysctlConfig{
+							Profiles: []coreosconfig.SysctlProfile{coreosconfig.SysctlProfileNetworkHeavy},
+							Settings: map[string]string{
+								"net.core.somaxconn": "4096",
+								"vm.max_map_count":   "262144",
+							},
+						},
+					},
+				}
+				oscActuator = NewActuator(mgr, extensionConfig)
+				_, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(extensionUnits).To(ContainElement(extensionsv1alpha1.Unit{
+					Name:      "systemd-sysctl.service",
+					Command:   ptr.To(extensionsv1alpha1.CommandRestart),
+					FilePaths: []string{"/etc/sysctl.d/99-os-coreos.conf"},
+				}))
+				Expect(extensionFiles).To(ContainElement(extensionsv1alpha1.File{
+					Path:        "/etc/sysctl.d/99-os-coreos.conf",
+					Permissions: ptr.To[uint32](0644),
+					Content: extensionsv1alpha1.FileContent{
+						Inline: &extensionsv1alpha1.FileContentInline{
+							Data: `net.core.netdev_max_backlog = 16384
+net.core.rmem_max = 16777216
+net.core.somaxconn = 4096
+net.core.wmem_max = 16777216
+net.ipv4.tcp_max_syn_backlog = 8096
+net.ipv4.tcp_rmem = 4096 87380 16777216
+net.ipv4.tcp_tw_reuse = 1
+net.ipv4.tcp_wmem = 4096 65536 16777216
+net.netfilter.nf_conntrack_max = 1048576
+vm.max_map_count = 262144
 `,
 						},
 					},
 				}))
 			})
 			It("should not return an error", func() {
-				userData, extensionUnits, extensionFiles, _, err := actuator.Reconcile(ctx, log, osc)
+				userData, extensionUnits, extensionFiles, _, err := oscActuator.Reconcile(ctx, log, osc)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(userData).To(BeEmpty())
 				Expect(extensionUnits).To(ConsistOf(
 					extensionsv1alpha1.Unit{Name: "systemd-timesyncd.service", Command: ptr.To(extensionsv1alpha1.CommandStart), Enable: ptr.To(true)},
 					extensionsv1alpha1.Unit{Name: "ntpd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
+					extensionsv1alpha1.Unit{Name: "chronyd.service", Command: ptr.To(extensionsv1alpha1.CommandStop), Enable: ptr.To(false)},
 					extensionsv1alpha1.Unit{
 						Name:    "update-engine.service",
 						Command: new(extensionsv1alpha1.CommandStop),
@@ -486,10 +2008,13 @@ Restart=no
 						DropIns: []extensionsv1alpha1.DropIn{{
 							Name: "10-configure-cgroup-driver.conf",
 							Content: `[Service]
-ExecStartPre=/opt/bin/kubelet_cgroup_driver.sh
+ExecStart=
+ExecStart=/opt/bin/kubelet \
+    --bootstrap-kubeconfig=/var/lib/kubelet/kubeconfig-bootstrap \
+    --config=/var/lib/kubelet/config/kubelet --config-dir=/etc/kubernetes/kubelet.conf.d $KUBELET_EXTRA_ARGS
 `,
 						}},
-						FilePaths: []string{"/opt/bin/kubelet_cgroup_driver.sh"},
+						FilePaths: []string{"/etc/kubernetes/kubelet.conf.d/10-os-coreos-cgroup-driver.conf"},
 					},
 					extensionsv1alpha1.Unit{
 						Name: "containerd.service",
@@ -510,19 +2035,11 @@ ExecStart=/usr/bin/containerd --config /etc/containerd/config.toml`,
 						Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: "install sctp /bin/true"}},
 					},
 					extensionsv1alpha1.File{
-						Path:        "/opt/bin/kubelet_cgroup_driver.sh",
-						Permissions: ptr.To[uint32](0755),
-						Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `#!/bin/bash
-
-KUBELET_CONFIG=/var/lib/kubelet/config/kubelet
-
-if [[ -e /sys/fs/cgroup/cgroup.controllers ]]; then
-        echo "CGroups V2 are used!"
-        echo "=> Patch kubelet to use systemd as cgroup driver"
-        sed -i "s/cgroupDriver: cgroupfs/cgroupDriver: systemd/" "$KUBELET_CONFIG"
-else
-        echo "No CGroups V2 used by system"
-fi
+						Path:        "/etc/kubernetes/kubelet.conf.d/10-os-coreos-cgroup-driver.conf",
+						Permissions: ptr.To[uint32](0644),
+						Content: extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: `apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+cgroupDriver: systemd
 `}},
 					},
 				))
@@ -530,3 +2047,17 @@ fi
 		})
 	})
 })
+
+func newCluster(name, providerType string, workers ...gardencorev1beta1.Worker) *extensionsv1alpha1.Cluster {
+	shoot := &gardencorev1beta1.Shoot{
+		TypeMeta: metav1.TypeMeta{APIVersion: gardencorev1beta1.SchemeGroupVersion.String(), Kind: "Shoot"},
+		Spec:     gardencorev1beta1.ShootSpec{Provider: gardencorev1beta1.Provider{Type: providerType, Workers: workers}},
+	}
+	raw, err := stdjson.Marshal(shoot)
+	Expect(err).NotTo(HaveOccurred())
+
+	return &extensionsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       extensionsv1alpha1.ClusterSpec{Shoot: runtime.RawExtension{Raw: raw}},
+	}
+}
@@ -6,15 +6,23 @@ package operatingsystemconfig
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/gardener/gardener/extensions/pkg/controller/operatingsystemconfig"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	predicateutils "github.com/gardener/gardener/pkg/controllerutils/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// DefaultAddOptions are the default controller.Options for AddToManager.
-var DefaultAddOptions = AddOptions{}
+var (
+	// DefaultTypes are the types of operating system configs the controller handles by default.
+	DefaultTypes = []string{"coreos", "flatcar", "flatcar-alpha", "flatcar-beta", "flatcar-stable", "flatcar-lts"}
+
+	// DefaultAddOptions are the default controller.Options for AddToManager.
+	DefaultAddOptions = AddOptions{}
+)
 
 // AddOptions are the options for adding the controller to the manager.
 type AddOptions struct {
@@ -31,11 +39,37 @@ type AddOptions struct {
 // AddToManagerWithOptions adds a controller with the given Options to the given manager.
 // The opts.Reconciler is being set with a newly instantiated actuator.
 func AddToManagerWithOptions(ctx context.Context, mgr manager.Manager, opts AddOptions) error {
+	types := opts.ExtensionConfig.Types
+	if len(types) == 0 {
+		types = DefaultTypes
+	}
+
+	actuator := newActuator(mgr, opts.ExtensionConfig)
+	if opts.ExtensionConfig.ConfigFile != "" && opts.ExtensionConfig.LoadExtensionConfig != nil {
+		if err := mgr.Add(&configReloader{
+			log:        mgr.GetLogger().WithName("config-reloader"),
+			client:     mgr.GetClient(),
+			actuator:   actuator,
+			file:       opts.ExtensionConfig.ConfigFile,
+			load:       opts.ExtensionConfig.LoadExtensionConfig,
+			elected:    mgr.Elected(),
+			predicates: predicateutils.AddTypeAndClassPredicates(nil, opts.ExtensionClasses, types...),
+		}); err != nil {
+			return fmt.Errorf("failed to add config reloader: %w", err)
+		}
+	}
+
+	if opts.ExtensionConfig.ValidateShoots {
+		mgr.GetWebhookServer().Register(ShootValidatorPath, &admission.Webhook{
+			Handler: &shootValidator{actuator: actuator, types: types},
+		})
+	}
+
 	return operatingsystemconfig.Add(mgr, operatingsystemconfig.AddArgs{
-		Actuator:          NewActuator(mgr, opts.ExtensionConfig),
+		Actuator:          actuator,
 		ControllerOptions: opts.Controller,
 		Predicates:        operatingsystemconfig.DefaultPredicates(ctx, mgr, opts.IgnoreOperationAnnotation),
-		Types:             []string{"coreos", "flatcar", "flatcar-alpha", "flatcar-beta", "flatcar-stable", "flatcar-lts"},
+		Types:             types,
 		ExtensionClasses:  opts.ExtensionClasses,
 	})
 }
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+)
+
+// kubeletConfigDropInDir is the directory the kubelet reads configuration drop-ins from, see
+// https://kubernetes.io/docs/tasks/administer-cluster/kubelet-config-file/#kubelet-conf-d.
+// Values in the drop-ins take precedence over the kubelet configuration file written by Gardener.
+const kubeletConfigDropInDir = "/etc/kubernetes/kubelet.conf.d"
+
+// CapabilityCgroupVersion is the name of the machine image capability declaring the cgroup version the image boots
+// with, either v1 or v2. It decides the cgroup driver if the OSC does not specify one.
+const CapabilityCgroupVersion = "cgroupVersion"
+
+// getCgroupDriver returns the cgroup driver to be used by the kubelet and the container runtime. It is taken from the
+// CRI configuration of the OSC, or else from the cgroup version capability of the machine image of the worker pool.
+// All supported Flatcar releases boot with cgroups v2 (unified hierarchy), so the systemd cgroup driver is used if
+// neither decides.
+func (a *actuator) getCgroupDriver(ctx context.Context, osc *extensionsv1alpha1.OperatingSystemConfig) (extensionsv1alpha1.CgroupDriverName, error) {
+	if osc.Spec.CRIConfig != nil && osc.Spec.CRIConfig.CgroupDriver != nil {
+		return *osc.Spec.CRIConfig.CgroupDriver, nil
+	}
+
+	cluster, err := extensionscontroller.GetCluster(ctx, a.client, osc.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("error getting cluster: %w", err)
+	}
+	if machineImageCgroupVersion(cluster, osc) == "v1" {
+		return extensionsv1alpha1.CgroupDriverCgroupfs, nil
+	}
+	return extensionsv1alpha1.CgroupDriverSystemd, nil
+}
+
+// machineImageCgroupVersion returns the value of the cgroup version capability of the machine image version of the
+// worker pool the given operating system config belongs to. It is empty if the capability is unknown or the flavors of
+// the machine image version disagree.
+func machineImageCgroupVersion(cluster *extensionscontroller.Cluster, osc *extensionsv1alpha1.OperatingSystemConfig) string {
+	poolName, ok := osc.Labels[v1beta1constants.LabelWorkerPool]
+	if !ok || cluster == nil || cluster.Shoot == nil || cluster.CloudProfile == nil {
+		return ""
+	}
+
+	var image *gardencorev1beta1.ShootMachineImage
+	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		if worker.Name == poolName {
+			image = worker.Machine.Image
+		}
+	}
+	if image == nil || image.Version == nil {
+		return ""
+	}
+
+	for _, machineImage := range cluster.CloudProfile.Spec.MachineImages {
+		if machineImage.Name != image.Name {
+			continue
+		}
+		for _, version := range machineImage.Versions {
+			if version.Version != *image.Version {
+				continue
+			}
+			var cgroupVersion string
+			for _, flavor := range version.CapabilityFlavors {
+				capabilities := gardencorev1beta1.GetCapabilitiesWithAppliedDefaults(flavor.Capabilities, cluster.CloudProfile.Spec.MachineCapabilities)
+				values := capabilities[CapabilityCgroupVersion]
+				if len(values) != 1 || (cgroupVersion != "" && cgroupVersion != values[0]) {
+					return ""
+				}
+				cgroupVersion = values[0]
+			}
+			return cgroupVersion
+		}
+	}
+	return ""
+}
+
+// generateKubeletCgroupDriverConfig returns a kubelet configuration drop-in which sets the given cgroup driver.
+func generateKubeletCgroupDriverConfig(cgroupDriver extensionsv1alpha1.CgroupDriverName) string {
+	return `apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+cgroupDriver: ` + string(cgroupDriver) + `
+`
+}
+
+// generateKubeletConfigDirDropIn returns a drop-in for the kubelet unit of the given OSC, which passes the configuration
+// drop-in directory to the kubelet. It repeats the command line of the kubelet unit written by Gardener with the
+// additional flag, so that KUBELET_EXTRA_ARGS stays untouched. The drop-in follows the unit, since the OSC is reconciled
+// whenever Gardener changes it. It fails if the OSC has no kubelet unit with a command line, since an empty drop-in
+// would silently drop the configuration of the cgroup driver.
+func generateKubeletConfigDirDropIn(osc *extensionsv1alpha1.OperatingSystemConfig) (string, error) {
+	execStart := kubeletExecStart(osc)
+	if execStart == "" {
+		return "", fmt.Errorf("the operating system config has no kubelet.service unit with an ExecStart command")
+	}
+
+	flag := "--config-dir=" + kubeletConfigDropInDir
+	// The extra arguments of the node stay last, so that they still take precedence.
+	if before, ok := strings.CutSuffix(execStart, " $KUBELET_EXTRA_ARGS"); ok {
+		execStart = before + " " + flag + " $KUBELET_EXTRA_ARGS"
+	} else {
+		execStart += " " + flag
+	}
+	return "[Service]\nExecStart=\nExecStart=" + execStart + "\n", nil
+}
+
+// kubeletExecStart returns the command line of the kubelet unit of the given OSC including continuation lines, or an
+// empty string if there is none.
+func kubeletExecStart(osc *extensionsv1alpha1.OperatingSystemConfig) string {
+	for _, unit := range osc.Spec.Units {
+		if unit.Name != "kubelet.service" || unit.Content == nil {
+			continue
+		}
+
+		var (
+			lines []string
+			found bool
+		)
+		for _, line := range strings.Split(*unit.Content, "\n") {
+			if !found {
+				value, ok := strings.CutPrefix(strings.TrimSpace(line), "ExecStart=")
+				if !ok || value == "" {
+					continue
+				}
+				found, line = true, value
+			}
+			lines = append(lines, line)
+			if !strings.HasSuffix(line, "\\") {
+				break
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	return ""
+}
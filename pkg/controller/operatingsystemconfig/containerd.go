@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/sprig/v3"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils/structuredmap"
+	"k8s.io/utils/ptr"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+const (
+	// containerdConfigPath is the path of the containerd configuration file, see also templates/11-exec_config.conf.
+	containerdConfigPath = "/etc/containerd/config.toml"
+	// containerdConfigImportsDir is the directory of additional containerd configuration files imported by the main
+	// configuration file. gardener-node-agent configures the same import on existing nodes.
+	containerdConfigImportsDir = "/etc/containerd/conf.d"
+	// containerdCertsDir is the directory containing the hosts.toml files of the registries. It is the same directory
+	// gardener-node-agent uses, so that it takes over the files written during provisioning.
+	containerdCertsDir = "/etc/containerd/certs.d"
+	// containerdRegistryAuthConfigPath is the path of the imported configuration file containing the registry
+	// credentials. It is only readable by root as it contains secrets.
+	containerdRegistryAuthConfigPath = containerdConfigImportsDir + "/registry-auth.toml"
+	// defaultContainerdConfigVersion is understood by both containerd 1.7 and 2.x.
+	defaultContainerdConfigVersion = 2
+	// defaultContainerdSnapshotter is the default snapshotter of the containerd CRI plugin.
+	defaultContainerdSnapshotter = "overlayfs"
+)
+
+// containerdConfigV2 is the subset of the containerd configuration file version 2 (containerd 1.x) managed by this
+// extension. See https://github.com/containerd/containerd/blob/release/1.7/docs/cri/config.md.
+type containerdConfigV2 struct {
+	Version int                       `toml:"version"`
+	Imports []string                  `toml:"imports"`
+	Plugins containerdConfigV2Plugins `toml:"plugins"`
+}
+
+type containerdConfigV2Plugins struct {
+	CRI containerdConfigV2CRI `toml:"io.containerd.grpc.v1.cri"`
+}
+
+type containerdConfigV2CRI struct {
+	SandboxImage string                          `toml:"sandbox_image,omitempty"`
+	Containerd   containerdConfigRuntimeSettings `toml:"containerd"`
+	Registry     containerdConfigRegistry        `toml:"registry"`
+}
+
+// containerdConfigV3 is the subset of the containerd configuration file version 3 (containerd 2.x) managed by this
+// extension. See https://github.com/containerd/containerd/blob/release/2.0/docs/cri/config.md.
+type containerdConfigV3 struct {
+	Version int                       `toml:"version"`
+	Imports []string                  `toml:"imports"`
+	Plugins containerdConfigV3Plugins `toml:"plugins"`
+}
+
+type containerdConfigV3Plugins struct {
+	Images  containerdConfigV3Images  `toml:"io.containerd.cri.v1.images"`
+	Runtime containerdConfigV3Runtime `toml:"io.containerd.cri.v1.runtime"`
+}
+
+type containerdConfigV3Images struct {
+	Snapshotter  string                         `toml:"snapshotter"`
+	PinnedImages containerdConfigV3PinnedImages `toml:"pinned_images,omitempty"`
+	Registry     containerdConfigRegistry       `toml:"registry"`
+}
+
+type containerdConfigV3PinnedImages struct {
+	Sandbox string `toml:"sandbox,omitempty"`
+}
+
+type containerdConfigV3Runtime struct {
+	Containerd containerdConfigRuntimeSettings `toml:"containerd"`
+}
+
+// containerdConfigRuntimeSettings is the runtime section of the CRI plugin, which is the same for both versions apart
+// from the snapshotter, which moved to the images plugin in version 3.
+type containerdConfigRuntimeSettings struct {
+	Snapshotter        string                             `toml:"snapshotter,omitempty"`
+	DefaultRuntimeName string                             `toml:"default_runtime_name"`
+	Runtimes           map[string]containerdConfigRuntime `toml:"runtimes"`
+}
+
+type containerdConfigRuntime struct {
+	RuntimeType string                      `toml:"runtime_type"`
+	Options     containerdConfigRuncOptions `toml:"options"`
+}
+
+type containerdConfigRuncOptions struct {
+	SystemdCgroup bool `toml:"SystemdCgroup"`
+}
+
+type containerdConfigRegistry struct {
+	ConfigPath string `toml:"config_path"`
+}
+
+// containerdPluginPathReplacements maps plugin paths of the OSC, which are given for config version 2, to their
+// location in config version 3. It mirrors the replacements done by gardener-node-agent.
+var containerdPluginPathReplacements = map[string]structuredmap.Path{
+	"plugins/io.containerd.grpc.v1.cri/containerd/runtimes": {"plugins", "io.containerd.cri.v1.runtime", "containerd", "runtimes"},
+}
+
+// generateContainerdConfig renders the containerd configuration file in the configured version. The file only contains
+// the settings managed by this extension and the CRI configuration of the OSC, all others keep the defaults of the
+// containerd version on the image.
+func generateContainerdConfig(config *coreosconfig.ExtensionConfig, osc *extensionsv1alpha1.OperatingSystemConfig, cgroupDriver extensionsv1alpha1.CgroupDriverName) (string, error) {
+	var (
+		containerdConfig = ptr.Deref(config.Containerd, coreosconfig.ContainerdConfig{})
+		version          = ptr.Deref(containerdConfig.ConfigVersion, defaultContainerdConfigVersion)
+		sandboxImage     = ptr.Deref(containerdConfig.SandboxImage, "")
+		snapshotter      = ptr.Deref(containerdConfig.Snapshotter, defaultContainerdSnapshotter)
+		registry         = containerdConfigRegistry{ConfigPath: containerdCertsDir}
+		runtimeSettings  = containerdConfigRuntimeSettings{
+			DefaultRuntimeName: "runc",
+			Runtimes: map[string]containerdConfigRuntime{
+				"runc": {
+					RuntimeType: "io.containerd.runc.v2",
+					Options:     containerdConfigRuncOptions{SystemdCgroup: cgroupDriver == extensionsv1alpha1.CgroupDriverSystemd},
+				},
+			},
+		}
+		plugins []extensionsv1alpha1.PluginConfig
+		out     any
+	)
+
+	// The sandbox image of the OSC is the one of the shoot, hence it takes precedence over the configured one.
+	if criContainerdConfig := getCRIContainerdConfig(osc); criContainerdConfig != nil {
+		if criContainerdConfig.SandboxImage != "" {
+			sandboxImage = criContainerdConfig.SandboxImage
+		}
+		plugins = criContainerdConfig.Plugins
+	}
+
+	switch version {
+	case 2:
+		runtimeSettings.Snapshotter = snapshotter
+		out = containerdConfigV2{
+			Version: 2,
+			Imports: []string{containerdConfigImportsDir + "/*.toml"},
+			Plugins: containerdConfigV2Plugins{CRI: containerdConfigV2CRI{
+				SandboxImage: sandboxImage,
+				Containerd:   runtimeSettings,
+				Registry:     registry,
+			}},
+		}
+	case 3:
+		out = containerdConfigV3{
+			Version: 3,
+			Imports: []string{containerdConfigImportsDir + "/*.toml"},
+			Plugins: containerdConfigV3Plugins{
+				Images: containerdConfigV3Images{
+					Snapshotter:  snapshotter,
+					PinnedImages: containerdConfigV3PinnedImages{Sandbox: sandboxImage},
+					Registry:     registry,
+				},
+				Runtime: containerdConfigV3Runtime{Containerd: runtimeSettings},
+			},
+		}
+	default:
+		return "", fmt.Errorf("unsupported containerd config version: %d", version)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(out); err != nil {
+		return "", fmt.Errorf("failed to encode containerd config: %w", err)
+	}
+
+	if len(plugins) == 0 {
+		return buf.String(), nil
+	}
+
+	// The plugin configuration of the OSC consists of arbitrary paths, hence it is applied on the unstructured config.
+	content := map[string]any{}
+	if _, err := toml.Decode(buf.String(), &content); err != nil {
+		return "", fmt.Errorf("failed to decode containerd config: %w", err)
+	}
+	for _, pluginConfig := range plugins {
+		pluginPath := replaceContainerdPluginPath(append(structuredmap.Path{"plugins"}, pluginConfig.Path...), version)
+		if err := structuredmap.SetMapEntry(content, pluginPath, func(val any) (any, error) {
+			switch op := ptr.Deref(pluginConfig.Op, extensionsv1alpha1.AddPluginPathOperation); op {
+			case extensionsv1alpha1.AddPluginPathOperation:
+				values, ok := val.(map[string]any)
+				if !ok || values == nil {
+					values = map[string]any{}
+				}
+				if pluginConfig.Values == nil {
+					return values, nil
+				}
+				if err := json.Unmarshal(pluginConfig.Values.Raw, &values); err != nil {
+					return nil, err
+				}
+				return values, nil
+			case extensionsv1alpha1.RemovePluginPathOperation:
+				return nil, nil
+			default:
+				return nil, fmt.Errorf("operation %q is not supported", op)
+			}
+		}); err != nil {
+			return "", fmt.Errorf("failed to apply plugin configuration at %q: %w", strings.Join(pluginPath, "."), err)
+		}
+	}
+
+	buf.Reset()
+	if err := toml.NewEncoder(&buf).Encode(content); err != nil {
+		return "", fmt.Errorf("failed to encode containerd config: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// replaceContainerdPluginPath translates the given plugin path to the given config version.
+func replaceContainerdPluginPath(pluginPath structuredmap.Path, version int32) structuredmap.Path {
+	if version < 3 {
+		return pluginPath
+	}
+	for prefix, replacement := range containerdPluginPathReplacements {
+		prefixPath := strings.Split(prefix, "/")
+		if len(pluginPath) >= len(prefixPath) && slices.Equal(pluginPath[:len(prefixPath)], prefixPath) {
+			return append(slices.Clone(replacement), pluginPath[len(prefixPath):]...)
+		}
+	}
+	return pluginPath
+}
+
+//go:embed templates/containerd-hosts.toml.tpl
+var containerdHostsTemplateContent string
+
+var containerdHostsTemplate = template.Must(template.New("containerd-hosts").Funcs(sprig.TxtFuncMap()).Parse(containerdHostsTemplateContent))
+
+// generateContainerdHostsFiles renders a hosts.toml file for each registry of the CRI configuration of the OSC.
+// Registries which should be probed for readiness are skipped, they are added by gardener-node-agent once reachable.
+// The files are only written during provisioning. Afterwards gardener-node-agent keeps them in sync with the OSC, so
+// they must not be part of the reconcile path, which would conflict with the files of gardener-node-agent.
+func generateContainerdHostsFiles(osc *extensionsv1alpha1.OperatingSystemConfig) ([]extensionsv1alpha1.File, error) {
+	criContainerdConfig := getCRIContainerdConfig(osc)
+	if criContainerdConfig == nil {
+		return nil, nil
+	}
+
+	var files []extensionsv1alpha1.File
+	for _, registry := range criContainerdConfig.Registries {
+		if ptr.Deref(registry.ReadinessProbe, false) {
+			continue
+		}
+
+		hosts := make([]extensionsv1alpha1.RegistryHost, 0, len(registry.Hosts))
+		for _, host := range registry.Hosts {
+			if len(host.Capabilities) == 0 {
+				host.Capabilities = []extensionsv1alpha1.RegistryCapability{extensionsv1alpha1.PullCapability, extensionsv1alpha1.ResolveCapability}
+			}
+			hosts = append(hosts, host)
+		}
+
+		var out strings.Builder
+		if err := containerdHostsTemplate.Execute(&out, map[string]any{
+			"Server": ptr.Deref(registry.Server, ""),
+			"Hosts":  hosts,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to render hosts.toml for upstream %q: %w", registry.Upstream, err)
+		}
+
+		files = append(files, extensionsv1alpha1.File{
+			Path:        path.Join(containerdCertsDir, registry.Upstream, "hosts.toml"),
+			Content:     extensionsv1alpha1.FileContent{Inline: &extensionsv1alpha1.FileContentInline{Data: out.String()}},
+			Permissions: ptr.To[uint32](0644),
+		})
+	}
+	return files, nil
+}
+
+func getCRIContainerdConfig(osc *extensionsv1alpha1.OperatingSystemConfig) *extensionsv1alpha1.ContainerdConfig {
+	if osc.Spec.CRIConfig == nil {
+		return nil
+	}
+	return osc.Spec.CRIConfig.Containerd
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"text/template"
+
+	"github.com/Masterminds/semver/v3"
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+var (
+	//go:embed templates/containerd/run-command.sh.tpl
+	containerdTemplateContent string
+	containerdTemplate        = template.Must(template.New("containerd-setup").Parse(containerdTemplateContent))
+)
+
+// imageCustomizations returns the customizations enabled or disabled by the image version rules for the machine image
+// of the worker pool of the given operating system config. Customizations no rule applies to are not contained. The
+// cluster is only read if there are rules.
+func (a *actuator) imageCustomizations(ctx context.Context, config *coreosconfig.ExtensionConfig, osc *extensionsv1alpha1.OperatingSystemConfig) (map[coreosconfig.Customization]bool, error) {
+	if len(config.ImageVersionRules) == 0 {
+		return nil, nil
+	}
+
+	cluster, err := extensionscontroller.GetCluster(ctx, a.client, osc.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster: %w", err)
+	}
+
+	return customizationsForVersion(config.ImageVersionRules, machineImageVersion(cluster, osc)), nil
+}
+
+// machineImageVersion returns the machine image version of the worker pool the given operating system config belongs
+// to, or nil if it is unknown.
+func machineImageVersion(cluster *extensionscontroller.Cluster, osc *extensionsv1alpha1.OperatingSystemConfig) *semver.Version {
+	poolName, ok := osc.Labels[v1beta1constants.LabelWorkerPool]
+	if !ok || cluster == nil || cluster.Shoot == nil {
+		return nil
+	}
+
+	for _, worker := range cluster.Shoot.Spec.Provider.Workers {
+		if worker.Name != poolName || worker.Machine.Image == nil || worker.Machine.Image.Version == nil {
+			continue
+		}
+		version, err := semver.NewVersion(*worker.Machine.Image.Version)
+		if err != nil {
+			return nil
+		}
+		return version
+	}
+
+	return nil
+}
+
+// customizationsForVersion returns the customizations enabled or disabled by the rules matching the given version. Later
+// rules take precedence over earlier ones. No rule matches an unknown version.
+func customizationsForVersion(rules []coreosconfig.ImageVersionRule, version *semver.Version) map[coreosconfig.Customization]bool {
+	customizations := map[coreosconfig.Customization]bool{}
+	if version == nil {
+		return customizations
+	}
+
+	for _, rule := range rules {
+		// The constraints are validated together with the extension config.
+		constraint, err := semver.NewConstraint(rule.Versions)
+		if err != nil || !constraint.Check(version) {
+			continue
+		}
+		for _, customization := range rule.Enable {
+			customizations[customization] = true
+		}
+		for _, customization := range rule.Disable {
+			customizations[customization] = false
+		}
+	}
+
+	return customizations
+}
+
+// generateContainerdSetupScript renders the containerd setup script with the image specific workarounds.
+func generateContainerdSetupScript(customizations map[coreosconfig.Customization]bool) (string, error) {
+	// The logrotate path is checked on the node, unless a rule decides for the machine image version.
+	logrotatePath := "detect"
+	if enabled, ok := customizations[coreosconfig.CustomizationLogrotatePath]; ok {
+		if enabled {
+			logrotatePath = "enabled"
+		} else {
+			logrotatePath = "disabled"
+		}
+	}
+
+	var out bytes.Buffer
+	if err := containerdTemplate.Execute(&out, map[string]any{"logrotatePath": logrotatePath}); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
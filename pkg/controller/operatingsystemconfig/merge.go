@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/v1beta1"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/validation"
+)
+
+// mergeExtensionConfig merges the given raw provider config of a shoot into a copy of the given extension config. The
+// provider config is applied as strategic merge patch onto the extension config in the version of the provider config:
+//   - Scalars set in the provider config override the ones of the extension config.
+//   - Objects and maps are merged field by field, which also applies to fields added to the API later on.
+//   - Lists are replaced, unless their field is annotated with the patch strategy merge. Lists of objects are then
+//     merged by their patch merge key.
+//   - Explicit nulls clear the field of the extension config.
+//
+// If the provider config switches the NTP daemon, the daemon sections of the extension config are dropped before. The
+// worker pool overrides of the provider config are appended to the ones of the extension config instead of replacing
+// them, so that the overrides of the extension config still apply and the ones of the provider config take precedence.
+func mergeExtensionConfig(config, shootConfig *coreosconfig.ExtensionConfig, shootConfigRaw []byte, gvk schema.GroupVersionKind) (*coreosconfig.ExtensionConfig, error) {
+	base := config.DeepCopy()
+	if base.NTP != nil && shootConfig.NTP != nil && shootConfig.NTP.Daemon != "" && shootConfig.NTP.Daemon != base.NTP.Daemon {
+		base.NTP.NTPD, base.NTP.Timesyncd, base.NTP.Chrony = nil, nil, nil
+	}
+
+	versioned, err := configScheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := configScheme.Convert(base, versioned, nil); err != nil {
+		return nil, fmt.Errorf("failed to convert extension config to %s: %w", gvk.GroupVersion(), err)
+	}
+	versioned.GetObjectKind().SetGroupVersionKind(gvk)
+
+	original, err := json.Marshal(versioned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode extension config: %w", err)
+	}
+	mergedRaw, err := strategicpatch.StrategicMergePatch(original, shootConfigRaw, versioned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge provider config: %w", err)
+	}
+
+	// Unknown and duplicate fields of the provider config are already rejected or ignored in lenient mode.
+	obj, _, err := decoder.Decode(mergedRaw, &gvk, nil)
+	if err != nil && !runtime.IsStrictDecodingError(err) {
+		return nil, fmt.Errorf("failed to decode merged config: %w", err)
+	}
+	merged := &coreosconfig.ExtensionConfig{}
+	if err := configScheme.Convert(obj, merged, nil); err != nil {
+		return nil, fmt.Errorf("failed to convert merged config: %w", err)
+	}
+	merged.WorkerPoolOverrides = base.WorkerPoolOverrides
+	for _, override := range shootConfig.WorkerPoolOverrides {
+		merged.WorkerPoolOverrides = append(merged.WorkerPoolOverrides, *override.DeepCopy())
+	}
+
+	return merged, nil
+}
+
+// withoutNulls removes the explicit nulls from the given raw provider config. They only clear fields when merging, and
+// are removed to validate the provider config on its own.
+func withoutNulls(raw []byte) ([]byte, error) {
+	var obj any
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	return json.Marshal(removeNulls(obj))
+}
+
+func removeNulls(obj any) any {
+	switch v := obj.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if value == nil {
+				delete(v, key)
+				continue
+			}
+			v[key] = removeNulls(value)
+		}
+	case []any:
+		for i := range v {
+			v[i] = removeNulls(v[i])
+		}
+	}
+	return obj
+}
+
+// mergeTypeOverrides merges the overrides for the given type of operating system config into a copy of the given
+// extension config, in the order they are listed. It returns the given config if no override applies.
+func mergeTypeOverrides(config *coreosconfig.ExtensionConfig, oscType string) (*coreosconfig.ExtensionConfig, error) {
+	overrides := config.TypeOverrides
+	for _, override := range overrides {
+		if !slices.Contains(override.Types, oscType) {
+			continue
+		}
+		var err error
+		if config, err = mergeOverride(config, &override.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// mergeWorkerPoolOverrides merges the overrides for the given worker pool into a copy of the given config, in the order
+// they are listed. It returns the given config if no override applies.
+func mergeWorkerPoolOverrides(config *coreosconfig.ExtensionConfig, poolName string) (*coreosconfig.ExtensionConfig, error) {
+	overrides := config.WorkerPoolOverrides
+	for _, override := range overrides {
+		if !slices.Contains(override.Pools, poolName) {
+			continue
+		}
+		var err error
+		if config, err = mergeOverride(config, &override.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// mergeProfile merges the settings of the profile with the given name into a copy of the given config. It returns the
+// given config if no profile is selected.
+func mergeProfile(config *coreosconfig.ExtensionConfig, name string) (*coreosconfig.ExtensionConfig, error) {
+	if name == "" {
+		return config, nil
+	}
+	if errs := validation.ValidateProfileSelection(name, config.Profiles, field.NewPath("profile")); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	profile := config.Profiles[slices.IndexFunc(config.Profiles, func(p coreosconfig.ConfigProfile) bool { return p.Name == name })]
+	merged, err := mergeOverride(config, &profile.Config)
+	if err != nil {
+		return nil, err
+	}
+	merged.Profile = name
+	return merged, nil
+}
+
+// mergeOverride merges the settings of an override into a copy of the given config in the same way as the provider
+// config of a shoot.
+func mergeOverride(config, override *coreosconfig.ExtensionConfig) (*coreosconfig.ExtensionConfig, error) {
+	versioned := &v1beta1.ExtensionConfig{}
+	if err := configScheme.Convert(override, versioned, nil); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(versioned)
+	if err != nil {
+		return nil, err
+	}
+	return mergeExtensionConfig(config, override, raw, v1beta1.SchemeGroupVersion.WithKind("ExtensionConfig"))
+}
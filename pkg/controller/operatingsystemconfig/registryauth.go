@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+// containerdRegistryAuth is the auth section of a registry in the configuration of the CRI images plugin.
+type containerdRegistryAuth struct {
+	Username      string `toml:"username,omitempty" json:"username,omitempty"`
+	Password      string `toml:"password,omitempty" json:"password,omitempty"`
+	Auth          string `toml:"auth,omitempty" json:"auth,omitempty"`
+	IdentityToken string `toml:"identitytoken,omitempty" json:"identitytoken,omitempty"`
+}
+
+// dockerConfigJSON is the content of a Secret of type kubernetes.io/dockerconfigjson.
+type dockerConfigJSON struct {
+	Auths map[string]containerdRegistryAuth `json:"auths"`
+}
+
+// generateContainerdRegistryAuthConfig resolves the registry credentials of the given config from the referenced
+// Secrets in the given namespace and renders them as containerd configuration file, which is imported by the main
+// configuration file. An empty string is returned if no credentials are configured.
+// Errors only name the Secret and registry, they never contain the credentials themselves.
+func generateContainerdRegistryAuthConfig(ctx context.Context, cl client.Client, namespace string, config *coreosconfig.ExtensionConfig) (string, error) {
+	containerdConfig := ptr.Deref(config.Containerd, coreosconfig.ContainerdConfig{})
+	if len(containerdConfig.RegistryAuth) == 0 {
+		return "", nil
+	}
+
+	registryConfigs := map[string]any{}
+	for _, registryAuth := range containerdConfig.RegistryAuth {
+		secret := &corev1.Secret{}
+		if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: registryAuth.SecretRef.Name}, secret); err != nil {
+			return "", fmt.Errorf("failed to get secret %q: %w", registryAuth.SecretRef.Name, err)
+		}
+		auth, err := registryAuthFromSecret(secret, registryAuth.Registry)
+		if err != nil {
+			return "", fmt.Errorf("failed to read credentials for registry %q from secret %q: %w", registryAuth.Registry, registryAuth.SecretRef.Name, err)
+		}
+		registryConfigs[registryAuth.Registry] = map[string]any{"auth": auth}
+	}
+
+	// containerd 1.x replaces the whole plugin configuration of the main configuration file with the one of the import,
+	// only containerd 2.x merges them, see validation.ValidateContainerdRegistryAuth.
+	if version := ptr.Deref(containerdConfig.ConfigVersion, defaultContainerdConfigVersion); version != 3 {
+		return "", fmt.Errorf("registry credentials require containerd config version 3, but version %d is used", version)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]any{
+		"version": 3,
+		"plugins": map[string]any{
+			"io.containerd.cri.v1.images": map[string]any{
+				"registry": map[string]any{"configs": registryConfigs},
+			},
+		},
+	}); err != nil {
+		return "", fmt.Errorf("failed to encode containerd registry auth config: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// registryAuthFromSecret reads the credentials of the given registry from the given Secret.
+func registryAuthFromSecret(secret *corev1.Secret, registry string) (containerdRegistryAuth, error) {
+	if secret.Type == corev1.SecretTypeDockerConfigJson {
+		var dockerConfig dockerConfigJSON
+		// The error of the decoder is not wrapped on purpose, as it might contain parts of the credentials.
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &dockerConfig); err != nil {
+			return containerdRegistryAuth{}, fmt.Errorf("key %q does not contain a valid docker config", corev1.DockerConfigJsonKey)
+		}
+		for key, auth := range dockerConfig.Auths {
+			if dockerConfigRegistryHost(key) == registry {
+				return auth, nil
+			}
+		}
+		return containerdRegistryAuth{}, fmt.Errorf("key %q does not contain an entry for the registry", corev1.DockerConfigJsonKey)
+	}
+
+	auth := containerdRegistryAuth{
+		Username:      string(secret.Data["username"]),
+		Password:      string(secret.Data["password"]),
+		Auth:          string(secret.Data["auth"]),
+		IdentityToken: string(secret.Data["identitytoken"]),
+	}
+	if (auth.Username == "" || auth.Password == "") && auth.Auth == "" && auth.IdentityToken == "" {
+		return containerdRegistryAuth{}, fmt.Errorf("secret must either contain the keys username and password, auth or identitytoken")
+	}
+	return auth, nil
+}
+
+// dockerConfigRegistryHost returns the host of a key of the auths section of a docker config, which might also be a URL
+// like https://index.docker.io/v1/.
+func dockerConfigRegistryHost(key string) string {
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+	host, _, _ := strings.Cut(key, "/")
+	return host
+}
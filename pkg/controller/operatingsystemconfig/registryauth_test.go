@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	containerdconfig "github.com/containerd/containerd/v2/cmd/containerd/server/config"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+var _ = Describe("Registry credentials", func() {
+	It("should keep the settings of the main containerd config when containerd imports the credentials", func() {
+		ctx := context.TODO()
+		fakeClient := fakeclient.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ref-registry-credentials", Namespace: "shoot--foo--bar"},
+			Data:       map[string][]byte{"username": []byte("foo"), "password": []byte("bar")},
+		}).Build()
+		config := &coreosconfig.ExtensionConfig{Containerd: &coreosconfig.ContainerdConfig{
+			ConfigVersion: ptr.To[int32](3),
+			RegistryAuth: []coreosconfig.RegistryAuth{{
+				Registry:  "registry.example.com",
+				SecretRef: corev1.LocalObjectReference{Name: "ref-registry-credentials"},
+			}},
+		}}
+		osc := &extensionsv1alpha1.OperatingSystemConfig{Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+			CRIConfig: &extensionsv1alpha1.CRIConfig{
+				Name:       extensionsv1alpha1.CRINameContainerD,
+				Containerd: &extensionsv1alpha1.ContainerdConfig{SandboxImage: "registry.k8s.io/pause:3.10"},
+			},
+		}}
+
+		mainConfig, err := generateContainerdConfig(config, osc, extensionsv1alpha1.CgroupDriverSystemd)
+		Expect(err).NotTo(HaveOccurred())
+		authConfig, err := generateContainerdRegistryAuthConfig(ctx, fakeClient, "shoot--foo--bar", config)
+		Expect(err).NotTo(HaveOccurred())
+
+		// The files are loaded with the configuration loader of containerd 2.x, which resolves and merges the imports.
+		dir := GinkgoT().TempDir()
+		importsDir := filepath.Join(dir, "conf.d")
+		Expect(os.Mkdir(importsDir, 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "config.toml"), []byte(strings.ReplaceAll(mainConfig, containerdConfigImportsDir, importsDir)), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(importsDir, filepath.Base(containerdRegistryAuthConfigPath)), []byte(authConfig), 0600)).To(Succeed())
+
+		loaded := &containerdconfig.Config{}
+		Expect(containerdconfig.LoadConfig(ctx, filepath.Join(dir, "config.toml"), loaded)).To(Succeed())
+		Expect(loaded.Plugins).To(HaveKeyWithValue("io.containerd.cri.v1.images", SatisfyAll(
+			HaveKeyWithValue("pinned_images", HaveKeyWithValue("sandbox", "registry.k8s.io/pause:3.10")),
+			HaveKeyWithValue("registry", SatisfyAll(
+				HaveKeyWithValue("config_path", containerdCertsDir),
+				HaveKeyWithValue("configs", HaveKeyWithValue("registry.example.com", HaveKeyWithValue("auth", SatisfyAll(
+					HaveKeyWithValue("username", "foo"),
+					HaveKeyWithValue("password", "bar"),
+				)))),
+			)),
+		)))
+		Expect(loaded.Plugins).To(HaveKeyWithValue("io.containerd.cri.v1.runtime", HaveKeyWithValue("containerd",
+			HaveKeyWithValue("runtimes", HaveKeyWithValue("runc", HaveKeyWithValue("options", HaveKeyWithValue("SystemdCgroup", true)))),
+		)))
+	})
+})
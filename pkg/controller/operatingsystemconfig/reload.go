@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	predicateutils "github.com/gardener/gardener/pkg/controllerutils/predicate"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+var (
+	configReloadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gardener_extension_os_coreos_config_reloads_total",
+		Help: "Number of reloads of the config file of the extension, by result.",
+	}, []string{"result"})
+	configLastReloadSuccessful = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gardener_extension_os_coreos_config_last_reload_successful",
+		Help: "Whether the last reload of the config file of the extension was successful.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(configReloadsTotal, configLastReloadSuccessful)
+}
+
+// configReloader watches the config file of the extension and swaps changed configs into the running actuator. The
+// operating system configs whose merged config changed are annotated to be reconciled again.
+type configReloader struct {
+	log      logr.Logger
+	client   client.Client
+	actuator *actuator
+	file     string
+	load     func([]byte) (*coreosconfig.ExtensionConfig, error)
+	// elected is closed once the manager is elected as leader, only the leader triggers reconciliations.
+	elected    <-chan struct{}
+	predicates []predicate.Predicate
+
+	// data is the content of the config file which was last reloaded.
+	data []byte
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The config is reloaded in all replicas, so that they are
+// up to date when they become leader.
+func (r *configReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable.
+func (r *configReloader) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher for config file: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	// Secrets are mounted with symlinks, which are replaced on updates, so the directory is watched instead of the file.
+	if err := watcher.Add(filepath.Dir(r.file)); err != nil {
+		return fmt.Errorf("failed to watch config file: %w", err)
+	}
+	// The file might have changed since it was read on startup.
+	r.reload(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			r.reload(ctx)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			r.log.Error(err, "Error watching config file", "file", r.file)
+		}
+	}
+}
+
+// reload reads the config file and swaps it into the actuator if it changed. Invalid configs are rejected and the
+// current config is kept.
+func (r *configReloader) reload(ctx context.Context) {
+	data, err := os.ReadFile(r.file)
+	if err != nil {
+		// The file is missing for a short time while the Secret is updated.
+		if !errors.Is(err, os.ErrNotExist) {
+			r.log.Error(err, "Failed to read config file", "file", r.file)
+		}
+		return
+	}
+	if bytes.Equal(data, r.data) {
+		return
+	}
+	r.data = data
+
+	config, err := r.load(data)
+	if err != nil {
+		configReloadsTotal.WithLabelValues("failure").Inc()
+		configLastReloadSuccessful.Set(0)
+		r.log.Error(err, "Rejecting invalid config file, keeping the current config", "file", r.file)
+		return
+	}
+	configReloadsTotal.WithLabelValues("success").Inc()
+	configLastReloadSuccessful.Set(1)
+
+	old := r.actuator.getExtensionConfig()
+	if equality.Semantic.DeepEqual(old, config) {
+		return
+	}
+	r.actuator.swapExtensionConfig(config)
+	r.log.Info("Reloaded config file", "file", r.file)
+
+	select {
+	case <-r.elected:
+		if err := r.enqueueAffected(ctx, old, config); err != nil {
+			r.log.Error(err, "Failed to trigger the reconciliation of operating system configs after reloading the config file")
+		}
+	default:
+	}
+}
+
+// enqueueAffected annotates the handled operating system configs, whose merged config differs between the given old and
+// new extension config, to be reconciled.
+func (r *configReloader) enqueueAffected(ctx context.Context, old, config *coreosconfig.ExtensionConfig) error {
+	oscList := &extensionsv1alpha1.OperatingSystemConfigList{}
+	if err := r.client.List(ctx, oscList); err != nil {
+		return fmt.Errorf("failed to list operating system configs: %w", err)
+	}
+
+	var errs []error
+	for _, osc := range oscList.Items {
+		if osc.DeletionTimestamp != nil || !predicateutils.EvalGeneric(&osc, r.predicates...) || !r.affected(&osc, old, config) {
+			continue
+		}
+
+		patch := client.MergeFrom(osc.DeepCopy())
+		metav1.SetMetaDataAnnotation(&osc.ObjectMeta, v1beta1constants.GardenerOperation, v1beta1constants.GardenerOperationReconcile)
+		if err := r.client.Patch(ctx, &osc, patch); err != nil {
+			errs = append(errs, fmt.Errorf("failed to annotate operating system config %s: %w", client.ObjectKeyFromObject(&osc), err))
+			continue
+		}
+		r.log.Info("Triggered reconciliation of operating system config after reloading the config file", "operatingSystemConfig", client.ObjectKeyFromObject(&osc))
+	}
+
+	return errors.Join(errs...)
+}
+
+// affected returns whether the merged config of the given operating system config differs between the given old and new
+// extension config.
+func (r *configReloader) affected(osc *extensionsv1alpha1.OperatingSystemConfig, old, config *coreosconfig.ExtensionConfig) bool {
+	oldMerged, oldErr := r.actuator.mergeConfiguration(logr.Discard(), old, osc)
+	merged, err := r.actuator.mergeConfiguration(logr.Discard(), config, osc)
+	if oldErr != nil || err != nil {
+		return oldErr == nil || err == nil || oldErr.Error() != err.Error()
+	}
+	return !equality.Semantic.DeepEqual(withoutAppliedSections(oldMerged), withoutAppliedSections(merged))
+}
+
+// withoutAppliedSections returns a copy of the given merged config without the sections, which were already applied
+// when merging.
+func withoutAppliedSections(config *coreosconfig.ExtensionConfig) *coreosconfig.ExtensionConfig {
+	config = config.DeepCopy()
+	config.Policy, config.TypeOverrides, config.WorkerPoolOverrides, config.Profiles = nil, nil, nil, nil
+	return config
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	predicateutils "github.com/gardener/gardener/pkg/controllerutils/predicate"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+	"github.com/gardener/gardener-extension-os-coreos/pkg/controller/config/validation"
+)
+
+var _ = Describe("Config reloader", func() {
+	const (
+		initialConfig = `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","enableDocker":false}`
+		changedConfig = `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","enableDocker":false,"typeOverrides":[{"types":["flatcar-lts"],"config":{"enableDocker":true}}]}`
+		invalidConfig = `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","containerd":{"configVersion":4}}`
+	)
+
+	var (
+		ctx        = context.TODO()
+		fakeClient client.Client
+		a          *actuator
+		reloader   *configReloader
+		elected    chan struct{}
+		file       string
+
+		newOSC = func(name, oscType string) *extensionsv1alpha1.OperatingSystemConfig {
+			return &extensionsv1alpha1.OperatingSystemConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shoot--foo--bar"},
+				Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: oscType},
+					Purpose:     extensionsv1alpha1.OperatingSystemConfigPurposeReconcile,
+				},
+			}
+		}
+		writeConfig = func(content string) {
+			ExpectWithOffset(1, os.WriteFile(file, []byte(content), 0600)).To(Succeed())
+		}
+		isAnnotated = func(name string) bool {
+			osc := &extensionsv1alpha1.OperatingSystemConfig{}
+			ExpectWithOffset(1, fakeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: "shoot--foo--bar"}, osc)).To(Succeed())
+			return osc.Annotations[v1beta1constants.GardenerOperation] == v1beta1constants.GardenerOperationReconcile
+		}
+	)
+
+	BeforeEach(func() {
+		fakeClient = fakeclient.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithObjects(
+			newOSC("lts", "flatcar-lts"),
+			newOSC("stable", "flatcar-stable"),
+			newOSC("other", "ubuntu"),
+		).Build()
+
+		a = &actuator{client: fakeClient, extensionConfig: Config{ExtensionConfig: &coreosconfig.ExtensionConfig{EnableDocker: ptr.To(false)}}}
+		elected = make(chan struct{})
+		file = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		reloader = &configReloader{
+			log:      logr.Discard(),
+			client:   fakeClient,
+			actuator: a,
+			file:     file,
+			load: func(data []byte) (*coreosconfig.ExtensionConfig, error) {
+				obj, _, err := decoder.Decode(data, nil, nil)
+				if err != nil {
+					return nil, err
+				}
+				config := &coreosconfig.ExtensionConfig{}
+				if err := configScheme.Convert(obj, config, nil); err != nil {
+					return nil, err
+				}
+				if errs := validation.ValidateExtensionConfig(config); len(errs) > 0 {
+					return nil, errs.ToAggregate()
+				}
+				return config, nil
+			},
+			elected:    elected,
+			predicates: predicateutils.AddTypeAndClassPredicates(nil, nil, "flatcar-lts", "flatcar-stable"),
+		}
+		writeConfig(initialConfig)
+	})
+
+	It("should swap a changed config and trigger the reconciliation of affected operating system configs", func() {
+		close(elected)
+		reloader.reload(ctx)
+		Expect(isAnnotated("lts")).To(BeFalse())
+
+		writeConfig(changedConfig)
+		reloader.reload(ctx)
+		Expect(a.getExtensionConfig().TypeOverrides).To(HaveLen(1))
+		Expect(isAnnotated("lts")).To(BeTrue())
+		Expect(isAnnotated("stable")).To(BeFalse())
+		Expect(isAnnotated("other")).To(BeFalse())
+	})
+
+	It("should not trigger reconciliations if the manager is not elected", func() {
+		writeConfig(changedConfig)
+		reloader.reload(ctx)
+		Expect(a.getExtensionConfig().TypeOverrides).To(HaveLen(1))
+		Expect(isAnnotated("lts")).To(BeFalse())
+	})
+
+	It("should keep the current config if the new one is invalid", func() {
+		close(elected)
+		failures := testutil.ToFloat64(configReloadsTotal.WithLabelValues("failure"))
+		current := a.getExtensionConfig()
+
+		writeConfig(invalidConfig)
+		reloader.reload(ctx)
+		Expect(a.getExtensionConfig()).To(BeIdenticalTo(current))
+		Expect(testutil.ToFloat64(configReloadsTotal.WithLabelValues("failure"))).To(Equal(failures + 1))
+		Expect(testutil.ToFloat64(configLastReloadSuccessful)).To(BeZero())
+		Expect(isAnnotated("lts")).To(BeFalse())
+
+		writeConfig(changedConfig)
+		reloader.reload(ctx)
+		Expect(a.getExtensionConfig().TypeOverrides).To(HaveLen(1))
+		Expect(testutil.ToFloat64(configLastReloadSuccessful)).To(Equal(float64(1)))
+	})
+
+	It("should reload the config file when it changes", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(reloader.Start(ctx)).To(Succeed())
+		}()
+
+		// The config file is read on start if it changes before the watch is established.
+		writeConfig(changedConfig)
+		Eventually(func() []coreosconfig.TypeOverride { return a.getExtensionConfig().TypeOverrides }).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+// ShootValidatorPath is the path the webhook validating the provider config of the machine images of shoots is served at.
+const ShootValidatorPath = "/webhooks/validate-shoot-coreos"
+
+// shootValidator validates the provider config of the machine images of shoots, which are handled by the extension. The
+// provider config is merged like for the operating system configs of the worker pools, so that invalid configs and
+// violations of the policy are rejected before the shoot is reconciled. It warns about changes of the provider config,
+// which are not applied to existing nodes.
+type shootValidator struct {
+	actuator *actuator
+	// types are the machine image names, which are handled by the extension.
+	types []string
+}
+
+// Handle implements admission.Handler.
+func (v *shootValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	shoot := &gardencorev1beta1.Shoot{}
+	if err := json.Unmarshal(req.Object.Raw, shoot); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode shoot: %w", err))
+	}
+	if shoot.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+	var oldShoot *gardencorev1beta1.Shoot
+	if req.Operation == admissionv1.Update {
+		oldShoot = &gardencorev1beta1.Shoot{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldShoot); err != nil {
+			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode old shoot: %w", err))
+		}
+	}
+
+	log := logr.FromContextOrDiscard(ctx)
+	extensionConfig := v.actuator.getExtensionConfig()
+
+	var (
+		allErrs  field.ErrorList
+		warnings []string
+	)
+	workersPath := field.NewPath("spec", "provider", "workers")
+	for i, worker := range shoot.Spec.Provider.Workers {
+		if worker.Machine.Image == nil || !slices.Contains(v.types, worker.Machine.Image.Name) {
+			continue
+		}
+		fldPath := workersPath.Index(i)
+
+		oldWorker := findWorker(oldShoot, worker.Name)
+		if oldWorker != nil && oldWorker.Machine.Image != nil && oldWorker.Machine.Image.Name == worker.Machine.Image.Name &&
+			imageConfigChanged(oldWorker, &worker) {
+			if changes := v.provisioningChanges(log, extensionConfig, *oldWorker, worker); len(changes) > 0 {
+				warnings = append(warnings, fmt.Sprintf("changing %s of the providerConfig is not applied to the existing nodes of worker pool %q", strings.Join(changes, ", "), worker.Name))
+			}
+		}
+
+		// Provider configs, which were admitted before, are not validated again, so that a stricter extension config does
+		// not block unrelated updates of the shoot. The controller reports them as configuration problem instead.
+		if worker.Machine.Image.ProviderConfig == nil || (oldWorker != nil && !imageConfigChanged(oldWorker, &worker)) {
+			continue
+		}
+		if _, err := v.actuator.mergeConfiguration(log, extensionConfig, workerOSC(worker)); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("machine", "image", "providerConfig"), field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	if len(allErrs) > 0 {
+		return admission.Denied(allErrs.ToAggregate().Error()).WithWarnings(warnings...)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
+
+// workerOSC returns an operating system config with the type, provider config and worker pool label the operating
+// system configs of the given worker are created with.
+func workerOSC(worker gardencorev1beta1.Worker) *extensionsv1alpha1.OperatingSystemConfig {
+	return &extensionsv1alpha1.OperatingSystemConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{v1beta1constants.LabelWorkerPool: worker.Name},
+		},
+		Spec: extensionsv1alpha1.OperatingSystemConfigSpec{
+			DefaultSpec: extensionsv1alpha1.DefaultSpec{
+				Type:           worker.Machine.Image.Name,
+				ProviderConfig: worker.Machine.Image.ProviderConfig,
+			},
+		},
+	}
+}
+
+// findWorker returns the worker with the given name of the given shoot, or nil if there is none.
+func findWorker(shoot *gardencorev1beta1.Shoot, name string) *gardencorev1beta1.Worker {
+	if shoot == nil {
+		return nil
+	}
+	for i := range shoot.Spec.Provider.Workers {
+		if shoot.Spec.Provider.Workers[i].Name == name {
+			return &shoot.Spec.Provider.Workers[i]
+		}
+	}
+	return nil
+}
+
+// imageConfigChanged returns whether the name or the provider config of the machine image differ between the given
+// workers.
+func imageConfigChanged(old, worker *gardencorev1beta1.Worker) bool {
+	if old.Machine.Image == nil {
+		return true
+	}
+	return old.Machine.Image.Name != worker.Machine.Image.Name ||
+		!equality.Semantic.DeepEqual(old.Machine.Image.ProviderConfig, worker.Machine.Image.ProviderConfig)
+}
+
+// provisioningChanges returns the paths of the settings, which differ between the merged configs of the given old and
+// new worker, and are not applied to existing nodes. Docker and the containerd configuration file are only set up during
+// provisioning, and removed kernel parameters keep their value until the node is rebooted. Registry credentials are
+// kept in sync on existing nodes, since containerd is restarted whenever they change. No changes are returned if one of
+// the configs cannot be merged, the new one is rejected by the validation anyway.
+func (v *shootValidator) provisioningChanges(log logr.Logger, extensionConfig *coreosconfig.ExtensionConfig, oldWorker, worker gardencorev1beta1.Worker) []string {
+	oldConfig, err := v.actuator.mergeConfiguration(log, extensionConfig, workerOSC(oldWorker))
+	if err != nil {
+		return nil
+	}
+	config, err := v.actuator.mergeConfiguration(log, extensionConfig, workerOSC(worker))
+	if err != nil {
+		return nil
+	}
+
+	var changes []string
+	if ptr.Deref(oldConfig.EnableDocker, false) != ptr.Deref(config.EnableDocker, false) {
+		changes = append(changes, "enableDocker")
+	}
+
+	oldContainerd, containerd := ptr.Deref(oldConfig.Containerd, coreosconfig.ContainerdConfig{}), ptr.Deref(config.Containerd, coreosconfig.ContainerdConfig{})
+	if ptr.Deref(oldContainerd.ConfigVersion, defaultContainerdConfigVersion) != ptr.Deref(containerd.ConfigVersion, defaultContainerdConfigVersion) {
+		changes = append(changes, "containerd.configVersion")
+	}
+	if ptr.Deref(oldContainerd.Snapshotter, defaultContainerdSnapshotter) != ptr.Deref(containerd.Snapshotter, defaultContainerdSnapshotter) {
+		changes = append(changes, "containerd.snapshotter")
+	}
+	if ptr.Deref(oldContainerd.SandboxImage, "") != ptr.Deref(containerd.SandboxImage, "") {
+		changes = append(changes, "containerd.sandboxImage")
+	}
+
+	oldSettings, err := sysctlSettings(oldConfig)
+	if err != nil {
+		return changes
+	}
+	settings, err := sysctlSettings(config)
+	if err != nil {
+		return changes
+	}
+	for _, key := range slices.Sorted(maps.Keys(oldSettings)) {
+		if _, ok := settings[key]; !ok {
+			changes = append(changes, fmt.Sprintf("sysctl.settings[%s]", key))
+		}
+	}
+
+	return changes
+}
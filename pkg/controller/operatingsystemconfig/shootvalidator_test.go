@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"context"
+	"encoding/json"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+var _ = Describe("Shoot validator", func() {
+	var (
+		ctx       = context.TODO()
+		validator *shootValidator
+
+		newShoot = func(image, providerConfig string) *gardencorev1beta1.Shoot {
+			shoot := &gardencorev1beta1.Shoot{}
+			shoot.Spec.Kubernetes.Version = "1.34.1"
+			shoot.Spec.Provider.Workers = []gardencorev1beta1.Worker{{
+				Name: "pool",
+				Machine: gardencorev1beta1.Machine{
+					Type:  "m5.large",
+					Image: &gardencorev1beta1.ShootMachineImage{Name: image, Version: ptr.To("4459.2.1")},
+				},
+				Maximum: 2,
+			}}
+			if providerConfig != "" {
+				shoot.Spec.Provider.Workers[0].Machine.Image.ProviderConfig = &runtime.RawExtension{Raw: []byte(providerConfig)}
+			}
+			return shoot
+		}
+		newRequest = func(operation admissionv1.Operation, shoot, oldShoot *gardencorev1beta1.Shoot) admission.Request {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: operation}}
+			raw, err := json.Marshal(shoot)
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			req.Object.Raw = raw
+			if oldShoot != nil {
+				raw, err := json.Marshal(oldShoot)
+				ExpectWithOffset(1, err).NotTo(HaveOccurred())
+				req.OldObject.Raw = raw
+			}
+			return req
+		}
+	)
+
+	BeforeEach(func() {
+		validator = &shootValidator{
+			actuator: &actuator{extensionConfig: Config{ExtensionConfig: &coreosconfig.ExtensionConfig{
+				EnableDocker: ptr.To(false),
+				Policy:       &coreosconfig.PolicyConfig{LockedFields: []string{"ntp"}},
+			}}},
+			types: []string{"flatcar"},
+		}
+	})
+
+	It("should allow valid provider configs", func() {
+		shoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","enableDocker":true}`)
+		response := validator.Handle(ctx, newRequest(admissionv1.Create, shoot, nil))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(BeEmpty())
+	})
+
+	It("should ignore machine images which are not handled by the extension", func() {
+		shoot := newShoot("ubuntu", `{"foo":"bar"}`)
+		Expect(validator.Handle(ctx, newRequest(admissionv1.Create, shoot, nil)).Allowed).To(BeTrue())
+	})
+
+	It("should reject invalid provider configs", func() {
+		shoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","enableDocker":true,"foo":"bar"}`)
+		response := validator.Handle(ctx, newRequest(admissionv1.Create, shoot, nil))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(And(
+			ContainSubstring("spec.provider.workers[0].machine.image.providerConfig"),
+			ContainSubstring("foo: Forbidden: unknown field"),
+		))
+	})
+
+	It("should reject provider configs referencing secrets which are no resources of the shoot", func() {
+		shoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","containerd":{"configVersion":3,"registryAuth":[{"registry":"registry.example.com","secretRef":{"name":"cloudprovider"}}]}}`)
+		response := validator.Handle(ctx, newRequest(admissionv1.Create, shoot, nil))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring(`containerd.registryAuth[0].secretRef.name: Invalid value: "cloudprovider": must start with "ref-"`))
+	})
+
+	It("should reject provider configs violating the policy", func() {
+		oldShoot := newShoot("flatcar", "")
+		shoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","ntp":{"enabled":false}}`)
+		response := validator.Handle(ctx, newRequest(admissionv1.Update, shoot, oldShoot))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring("violates the policy"))
+	})
+
+	It("should not validate unchanged provider configs again", func() {
+		oldShoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","ntp":{"enabled":false}}`)
+		shoot := oldShoot.DeepCopy()
+		shoot.Spec.Provider.Workers[0].Maximum = 3
+		Expect(validator.Handle(ctx, newRequest(admissionv1.Update, shoot, oldShoot)).Allowed).To(BeTrue())
+	})
+
+	It("should validate provider configs again if the machine image changes", func() {
+		oldShoot := newShoot("coreos", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","ntp":{"enabled":false}}`)
+		shoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","ntp":{"enabled":false}}`)
+		response := validator.Handle(ctx, newRequest(admissionv1.Update, shoot, oldShoot))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring("violates the policy"))
+	})
+
+	DescribeTable("should warn about changes of the provider config which are not applied to existing nodes",
+		func(oldProviderConfig, providerConfig string, matcher types.GomegaMatcher) {
+			oldShoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig",`+oldProviderConfig+`}`)
+			shoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig",`+providerConfig+`}`)
+			response := validator.Handle(ctx, newRequest(admissionv1.Update, shoot, oldShoot))
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Warnings).To(matcher)
+		},
+		Entry("enabling docker",
+			`"enableDocker":false`, `"enableDocker":true`,
+			ConsistOf(`changing enableDocker of the providerConfig is not applied to the existing nodes of worker pool "pool"`)),
+		Entry("changing the containerd config version",
+			`"containerd":{"configVersion":2}`, `"containerd":{"configVersion":3}`,
+			ConsistOf(ContainSubstring("changing containerd.configVersion of the providerConfig"))),
+		Entry("changing the snapshotter and the sandbox image",
+			`"containerd":{"sandboxImage":"registry.k8s.io/pause:3.9"}`, `"containerd":{"snapshotter":"native","sandboxImage":"registry.k8s.io/pause:3.10"}`,
+			ConsistOf(ContainSubstring("changing containerd.snapshotter, containerd.sandboxImage of the providerConfig"))),
+		Entry("removing kernel parameters",
+			`"sysctl":{"settings":{"vm.max_map_count":"262144","net.core.somaxconn":"4096"}}`, `"sysctl":{"settings":{"net.core.somaxconn":"8192"}}`,
+			ConsistOf(ContainSubstring("changing sysctl.settings[vm.max_map_count] of the providerConfig"))),
+		Entry("removing a kernel parameter profile",
+			`"sysctl":{"profiles":["network-heavy"],"settings":{"net.core.somaxconn":"8192"}}`, `"sysctl":{"settings":{"net.core.somaxconn":"8192"}}`,
+			ConsistOf(And(ContainSubstring("sysctl.settings[net.core.rmem_max]"), Not(ContainSubstring("sysctl.settings[net.core.somaxconn]"))))),
+		Entry("adding or changing kernel parameters",
+			`"sysctl":{"settings":{"net.core.somaxconn":"4096"}}`, `"sysctl":{"profiles":["network-heavy"],"settings":{"net.core.somaxconn":"8192","vm.max_map_count":"262144"}}`,
+			BeEmpty()),
+		Entry("changing registry credentials, which are kept in sync",
+			`"containerd":{"configVersion":3,"registryAuth":[{"registry":"registry.example.com","secretRef":{"name":"ref-foo"}}]}`, `"containerd":{"configVersion":3}`,
+			BeEmpty()),
+		Entry("keeping the effective settings",
+			`"enableDocker":false`, `"containerd":{"configVersion":2,"snapshotter":"overlayfs"}`,
+			BeEmpty()),
+	)
+
+	It("should not warn about new worker pools and changed machine images", func() {
+		oldShoot := newShoot("coreos", "")
+		shoot := newShoot("flatcar", `{"apiVersion":"config.coreos.os.extensions.gardener.cloud/v1beta1","kind":"ExtensionConfig","enableDocker":true}`)
+		shoot.Spec.Provider.Workers = append(shoot.Spec.Provider.Workers, *shoot.Spec.Provider.Workers[0].DeepCopy())
+		shoot.Spec.Provider.Workers[1].Name = "new-pool"
+		response := validator.Handle(ctx, newRequest(admissionv1.Update, shoot, oldShoot))
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(BeEmpty())
+	})
+})
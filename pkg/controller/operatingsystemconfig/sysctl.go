@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+// sysctlConfigPath is the file the kernel parameters are written to. It is named such that it sorts after the
+// 99-k8s-general.conf file written by Gardener, so explicitly configured values take precedence.
+const sysctlConfigPath = "/etc/sysctl.d/99-os-coreos.conf"
+
+// sysctlProfiles contains the kernel parameters of the predefined sysctl profiles.
+var sysctlProfiles = map[coreosconfig.SysctlProfile]map[string]string{
+	coreosconfig.SysctlProfileNetworkHeavy: {
+		"net.core.netdev_max_backlog":    "16384",
+		"net.core.rmem_max":              "16777216",
+		"net.core.somaxconn":             "32768",
+		"net.core.wmem_max":              "16777216",
+		"net.ipv4.tcp_max_syn_backlog":   "8096",
+		"net.ipv4.tcp_rmem":              "4096 87380 16777216",
+		"net.ipv4.tcp_tw_reuse":          "1",
+		"net.ipv4.tcp_wmem":              "4096 65536 16777216",
+		"net.netfilter.nf_conntrack_max": "1048576",
+	},
+	coreosconfig.SysctlProfileHardened: {
+		"fs.suid_dumpable":                       "0",
+		"kernel.dmesg_restrict":                  "1",
+		"kernel.kptr_restrict":                   "2",
+		"kernel.unprivileged_bpf_disabled":       "1",
+		"kernel.yama.ptrace_scope":               "1",
+		"net.core.bpf_jit_harden":                "2",
+		"net.ipv4.conf.all.accept_redirects":     "0",
+		"net.ipv4.conf.all.accept_source_route":  "0",
+		"net.ipv4.conf.all.send_redirects":       "0",
+		"net.ipv4.conf.default.accept_redirects": "0",
+		"net.ipv6.conf.all.accept_redirects":     "0",
+	},
+}
+
+// generateSysctlConfig renders the sysctl profiles and settings of the given config into the sysctl.d format.
+// Profiles are applied in the given order and explicit settings take precedence over all profiles. An empty string is
+// returned if no kernel parameters are configured.
+func generateSysctlConfig(config *coreosconfig.ExtensionConfig) (string, error) {
+	settings, err := sysctlSettings(config)
+	if err != nil || len(settings) == 0 {
+		return "", err
+	}
+
+	var out strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		fmt.Fprintf(&out, "%s = %s\n", key, strings.TrimSpace(settings[key]))
+	}
+	return out.String(), nil
+}
+
+// sysctlSettings returns the kernel parameters of the profiles and settings of the given config.
+func sysctlSettings(config *coreosconfig.ExtensionConfig) (map[string]string, error) {
+	settings := map[string]string{}
+	if config.Sysctl == nil {
+		return settings, nil
+	}
+
+	for _, profile := range config.Sysctl.Profiles {
+		profileSettings, ok := sysctlProfiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown sysctl profile: %s", profile)
+		}
+		maps.Copy(settings, profileSettings)
+	}
+	maps.Copy(settings, config.Sysctl.Settings)
+	return settings, nil
+}
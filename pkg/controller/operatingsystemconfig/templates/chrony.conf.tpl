@@ -0,0 +1,26 @@
+{{ range .Servers -}}
+server {{ .Address }} iburst{{ if .NTS }} nts{{ end }}
+{{ end -}}
+{{ range .Pools -}}
+pool {{ .Address }} iburst{{ if .NTS }} nts{{ end }}
+{{ end -}}
+{{ range .RefClocks -}}
+refclock PHC {{ .Device }}{{ with .Poll }} poll {{ . }}{{ end }}{{ with .DPoll }} dpoll {{ . }}{{ end }}
+{{ end }}
+driftfile /var/lib/chrony/drift
+makestep {{ .MakeStepThreshold }} {{ .MakeStepLimit }}
+{{ if .RTCSync -}}
+rtcsync
+{{ end -}}
+{{ range .Allow -}}
+allow {{ . }}
+{{ end -}}
+{{ if .NTS -}}
+ntsdumpdir /var/lib/chrony
+{{ end -}}
+{{ with .NTSTrustedCertsPath -}}
+ntstrustedcerts {{ . }}
+{{ end -}}
+{{ if .RequireAuthentication -}}
+authselectmode require
+{{ end -}}
@@ -0,0 +1,13 @@
+{{- if .Server }}
+server = {{ .Server | quote }}
+{{ end }}
+{{- range .Hosts }}
+[host.{{ .URL | quote }}]
+  capabilities = {{ .Capabilities | toJson }}
+  {{- if .CACerts }}
+  ca = {{ .CACerts | toJson }}
+  {{- end }}
+  {{- if .OverridePath }}
+  override_path = {{ .OverridePath }}
+  {{- end }}
+{{ end }}
@@ -6,20 +6,27 @@ ALTERNATE_LOGROTATE_PATH="/usr/bin/logrotate"
 
 CONTAINERD="/usr/bin/containerd"
 
-# initialize default containerd config if does not exist
+# The containerd config is rendered by the extension and written during provisioning.
+# Only fall back to the default config of the installed containerd version if it is missing.
 if [ ! -s "$CONTAINERD_CONFIG" ]; then
     mkdir -p "$(dirname "$CONTAINERD_CONFIG")"
     ${CONTAINERD} config default > "$CONTAINERD_CONFIG"
     chmod 0644 "$CONTAINERD_CONFIG"
-fi
 
-# if cgroups v2 are used, patch containerd configuration to use systemd cgroup driver
-if [[ -e /sys/fs/cgroup/cgroup.controllers ]]; then
-    sed -i "s/SystemdCgroup *= *false/SystemdCgroup = true/" "$CONTAINERD_CONFIG"
+    # if cgroups v2 are used, patch containerd configuration to use systemd cgroup driver
+    if [[ -e /sys/fs/cgroup/cgroup.controllers ]]; then
+        sed -i "s/SystemdCgroup *= *false/SystemdCgroup = true/" "$CONTAINERD_CONFIG"
+    fi
 fi
 
+{{ if eq .logrotatePath "detect" -}}
 # some flatcar versions have logrotate at /usr/bin instead of /usr/sbin
 if [ -f "$ALTERNATE_LOGROTATE_PATH" ]; then
     sed -i "s;/usr/sbin/logrotate;$ALTERNATE_LOGROTATE_PATH;" /etc/systemd/system/containerd-logrotate.service
     systemctl daemon-reload
 fi
+{{ else if eq .logrotatePath "enabled" -}}
+# the flatcar version of the image has logrotate at /usr/bin instead of /usr/sbin
+sed -i "s;/usr/sbin/logrotate;$ALTERNATE_LOGROTATE_PATH;" /etc/systemd/system/containerd-logrotate.service
+systemctl daemon-reload
+{{ end -}}
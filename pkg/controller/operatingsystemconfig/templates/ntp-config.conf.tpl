@@ -1,11 +1,24 @@
 {{- range .Servers }}
-server {{ . }} iburst
+server {{ . }} iburst{{ if $.Authentication }} key {{ $.Authentication.KeyID }}{{ end }}
+{{- end }}
+{{- range .Sources }}
+{{ if .Pool }}pool{{ else }}server{{ end }} {{ .Address }} iburst{{ if .Prefer }} prefer{{ end }}{{ with .MinPoll }} minpoll {{ . }}{{ end }}{{ with .MaxPoll }} maxpoll {{ . }}{{ end }}{{ with .Key }} key {{ . }}{{ end }}
 {{- end }}
 
-driftfile /var/lib/ntp/ntp.drift
+driftfile {{ .DriftFile | default "/var/lib/ntp/ntp.drift" }}
+{{- if .Authentication }}
+keys /etc/ntp.keys
+trustedkey {{ .Authentication.KeyID }}
+{{- end }}
+{{- if .Restrict }}
+{{- range .Restrict }}
+restrict {{ . }}
+{{- end }}
+{{- else }}
 restrict default nomodify nopeer noquery notrap limited kod
 restrict 127.0.0.1
 restrict [::1]
+{{- end }}
 
 {{ if .Interfaces -}}
 interface ignore wildcard
@@ -0,0 +1,11 @@
+[Time]
+NTP={{ join " " .Servers }}
+{{- if .FallbackServers }}
+FallbackNTP={{ join " " .FallbackServers }}
+{{- end }}
+{{- if .PollIntervalMinSec }}
+PollIntervalMinSec={{ .PollIntervalMinSec }}
+{{- end }}
+{{- if .PollIntervalMaxSec }}
+PollIntervalMaxSec={{ .PollIntervalMaxSec }}
+{{- end }}
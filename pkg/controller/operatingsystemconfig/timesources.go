@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	"k8s.io/utils/ptr"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+var (
+	// providerNTPServers are the time servers of the cloud providers, which are reachable from all their VMs without
+	// internet access.
+	providerNTPServers = map[string][]string{
+		"aws": {"169.254.169.123"},
+		"gcp": {"metadata.google.internal"},
+	}
+	// providerChronyRefClocks are the PTP hardware clocks exposed by the hypervisors of the cloud providers. Azure does not
+	// offer an NTP server, but the time of the Hyper-V host.
+	providerChronyRefClocks = map[string][]coreosconfig.ChronyRefClock{
+		"azure": {{Device: "/dev/ptp_hyperv", Poll: ptr.To[int32](3), DPoll: ptr.To[int32](-2)}},
+	}
+)
+
+// providerType returns the provider type of the shoot of the given cluster, or an empty string if it is unknown.
+func providerType(cluster *extensionscontroller.Cluster) string {
+	if cluster == nil || cluster.Shoot == nil {
+		return ""
+	}
+	return cluster.Shoot.Spec.Provider.Type
+}
+
+// withProviderTimeSources returns a copy of the given config in which the time sources of the configured daemon default
+// to the ones of the given cloud provider. Explicitly configured daemon sections are never modified, and no defaults are
+// applied if authentication is required, since the time sources of the cloud providers are not authenticated.
+func withProviderTimeSources(config *coreosconfig.ExtensionConfig, providerType string) *coreosconfig.ExtensionConfig {
+	if config.NTP == nil || ptr.Deref(config.NTP.RequireAuthentication, false) {
+		return config
+	}
+
+	var (
+		servers   = providerNTPServers[providerType]
+		refClocks = providerChronyRefClocks[providerType]
+		ntpConfig = config.NTP
+	)
+
+	switch {
+	case ntpConfig.Daemon == coreosconfig.SystemdTimesyncd && ntpConfig.Timesyncd == nil && len(servers) > 0:
+		config = config.DeepCopy()
+		config.NTP.Timesyncd = &coreosconfig.TimesyncdConfig{Servers: servers}
+	case ntpConfig.Daemon == coreosconfig.NTPD && ntpConfig.NTPD == nil && len(servers) > 0:
+		config = config.DeepCopy()
+		config.NTP.NTPD = &coreosconfig.NTPDConfig{Servers: servers}
+	case ntpConfig.Daemon == coreosconfig.Chrony && ntpConfig.Chrony == nil && len(refClocks) > 0:
+		config = config.DeepCopy()
+		config.NTP.Chrony = &coreosconfig.ChronyConfig{RefClocks: refClocks}
+	case ntpConfig.Daemon == coreosconfig.Chrony && ntpConfig.Chrony == nil && len(servers) > 0:
+		config = config.DeepCopy()
+		config.NTP.Chrony = &coreosconfig.ChronyConfig{}
+		for _, server := range servers {
+			config.NTP.Chrony.Servers = append(config.NTP.Chrony.Servers, coreosconfig.ChronySource{Address: server})
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package operatingsystemconfig
+
+import (
+	_ "embed"
+	"fmt"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"k8s.io/utils/ptr"
+
+	coreosconfig "github.com/gardener/gardener-extension-os-coreos/pkg/controller/config"
+)
+
+//go:embed templates/chrony-wait.service
+var chronyWaitUnitContent string
+
+const (
+	// defaultWaitForSyncTimeout is the time the kubelet waits for the clock to be synchronized by default.
+	defaultWaitForSyncTimeout = 2 * time.Minute
+
+	// kubeletWaitForSyncDropInName is the drop-in ordering the kubelet after time-sync.target.
+	kubeletWaitForSyncDropInName = "30-wait-for-time-sync.conf"
+	kubeletWaitForSyncDropIn     = `[Unit]
+Wants=time-sync.target
+After=time-sync.target
+`
+	// waitForSyncTimeoutDropInName is the drop-in bounding the time the wait unit delays time-sync.target. If it times
+	// out, time-sync.target is reached nevertheless, as it only wants the wait unit.
+	waitForSyncTimeoutDropInName = "10-timeout.conf"
+)
+
+// generateWaitForSyncUnits returns the units which delay time-sync.target until the clock is synchronized, or nil if
+// the kubelet shall not wait for it.
+// systemd-time-wait-sync waits for the kernel to report a synchronized clock, which is the case with systemd-timesyncd
+// and the kernel discipline of ntpd. chrony only reports it with rtcsync, hence it is asked directly via chronyc, and
+// systemd-time-wait-sync is disabled in case it was enabled for another daemon before.
+// The units are only enabled and not started, as the clock is only waited for on boot.
+func generateWaitForSyncUnits(config *coreosconfig.ExtensionConfig) []extensionsv1alpha1.Unit {
+	if config.NTP == nil || !ptr.Deref(config.NTP.Enabled, true) || config.NTP.WaitForSync == nil || !config.NTP.WaitForSync.Enabled {
+		return nil
+	}
+
+	timeout := defaultWaitForSyncTimeout
+	if config.NTP.WaitForSync.Timeout != nil {
+		timeout = config.NTP.WaitForSync.Timeout.Duration
+	}
+	timeoutDropIn := extensionsv1alpha1.DropIn{
+		Name:    waitForSyncTimeoutDropInName,
+		Content: fmt.Sprintf("[Service]\nTimeoutStartSec=%d\n", int64(timeout.Seconds())),
+	}
+
+	if config.NTP.Daemon == coreosconfig.Chrony {
+		return []extensionsv1alpha1.Unit{
+			{Name: "systemd-time-wait-sync.service", Enable: ptr.To(false)},
+			{Name: "chrony-wait.service", Enable: ptr.To(true), Content: ptr.To(chronyWaitUnitContent), DropIns: []extensionsv1alpha1.DropIn{timeoutDropIn}},
+		}
+	}
+	return []extensionsv1alpha1.Unit{
+		{Name: "systemd-time-wait-sync.service", Enable: ptr.To(true), DropIns: []extensionsv1alpha1.DropIn{timeoutDropIn}},
+	}
+}